@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.reminders
+ADD COLUMN IF NOT EXISTS source_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS reminders_user_id_source_key_idx ON data.reminders (user_id, source_key)
+WHERE
+    source_key IS NOT NULL
+    AND is_deleted = FALSE;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS data.reminders_user_id_source_key_idx;
+
+ALTER TABLE data.reminders
+DROP COLUMN IF EXISTS source_key;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.users
+ADD COLUMN IF NOT EXISTS calendar_token VARCHAR(64) UNIQUE;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE data.users
+DROP COLUMN IF EXISTS calendar_token;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS data.conversations (
+    chat_id BIGINT PRIMARY KEY,
+    telegram_id BIGINT NOT NULL,
+    flow VARCHAR(64) NOT NULL,
+    step VARCHAR(64) NOT NULL,
+    history TEXT[] NOT NULL DEFAULT '{}',
+    state JSONB NOT NULL DEFAULT '{}',
+    updated_at TIMESTAMP
+    WITH
+        TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE data.conversations;
+
+-- +goose StatementEnd
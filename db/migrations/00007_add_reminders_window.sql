@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.reminders
+ADD COLUMN IF NOT EXISTS window_floor INTERVAL NOT NULL DEFAULT '0',
+ADD COLUMN IF NOT EXISTS window_ceil INTERVAL NOT NULL DEFAULT '0';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE data.reminders
+DROP COLUMN IF EXISTS window_floor,
+DROP COLUMN IF EXISTS window_ceil;
+
+-- +goose StatementEnd
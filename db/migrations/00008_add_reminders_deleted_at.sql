@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.reminders
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP
+WITH
+    TIME ZONE;
+
+UPDATE data.reminders
+SET deleted_at = NOW()
+WHERE is_deleted = TRUE AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS reminders_user_id_deleted_at_idx ON data.reminders (user_id, deleted_at)
+WHERE
+    is_deleted = TRUE;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS data.reminders_user_id_deleted_at_idx;
+
+ALTER TABLE data.reminders
+DROP COLUMN IF EXISTS deleted_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.reminders
+ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(text, '')), 'A') ||
+    setweight(to_tsvector('russian', COALESCE(text, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(tag, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(prompt, '')), 'B') ||
+    setweight(to_tsvector('russian', COALESCE(prompt, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS reminders_search_vector_idx ON data.reminders USING GIN (search_vector);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS data.reminders_search_vector_idx;
+
+ALTER TABLE data.reminders
+DROP COLUMN IF EXISTS search_vector;
+
+-- +goose StatementEnd
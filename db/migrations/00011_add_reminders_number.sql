@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.users
+ADD COLUMN IF NOT EXISTS last_reminder_number INT NOT NULL DEFAULT 0;
+
+ALTER TABLE data.reminders
+ADD COLUMN IF NOT EXISTS number INT;
+
+UPDATE data.reminders rmd
+SET number = numbered.number
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY id) AS number
+    FROM data.reminders
+) numbered
+WHERE rmd.id = numbered.id;
+
+UPDATE data.users usr
+SET last_reminder_number = numbers.last_number
+FROM (
+    SELECT user_id, MAX(number) AS last_number
+    FROM data.reminders
+    GROUP BY user_id
+) numbers
+WHERE usr.id = numbers.user_id;
+
+ALTER TABLE data.reminders
+ALTER COLUMN number SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS reminders_user_id_number_idx ON data.reminders (user_id, number);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS data.reminders_user_id_number_idx;
+
+ALTER TABLE data.reminders
+DROP COLUMN IF EXISTS number;
+
+ALTER TABLE data.users
+DROP COLUMN IF EXISTS last_reminder_number;
+
+-- +goose StatementEnd
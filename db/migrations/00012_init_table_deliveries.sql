@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS data.deliveries (
+    chat_id BIGINT NOT NULL,
+    message_id INT NOT NULL,
+    reminder_id INT NOT NULL,
+    sent_at TIMESTAMP
+    WITH
+        TIME ZONE NOT NULL DEFAULT NOW(),
+        PRIMARY KEY (chat_id, message_id),
+        FOREIGN KEY (reminder_id) REFERENCES data.reminders (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS deliveries_sent_at_idx ON data.deliveries (sent_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE data.deliveries;
+
+-- +goose StatementEnd
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS data.groups (
+    chat_id BIGINT PRIMARY KEY,
+    title TEXT NOT NULL DEFAULT '',
+    last_reminder_number INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP
+    WITH
+        TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE data.reminders
+ADD COLUMN IF NOT EXISTS group_chat_id BIGINT REFERENCES data.groups (chat_id) ON DELETE CASCADE;
+
+-- group reminders are numbered per group, private ones per user
+DROP INDEX IF EXISTS data.reminders_user_id_number_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS reminders_user_id_number_idx ON data.reminders (user_id, number)
+WHERE
+    group_chat_id IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS reminders_group_chat_id_number_idx ON data.reminders (group_chat_id, number)
+WHERE
+    group_chat_id IS NOT NULL;
+
+-- commands sent in groups used to move delivery there, private chat id is the user's telegram id
+UPDATE data.users
+SET chat_id = telegram_id
+WHERE chat_id <> telegram_id;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS data.reminders_group_chat_id_number_idx;
+
+DELETE FROM data.reminders
+WHERE group_chat_id IS NOT NULL;
+
+DROP INDEX IF EXISTS data.reminders_user_id_number_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS reminders_user_id_number_idx ON data.reminders (user_id, number);
+
+ALTER TABLE data.reminders
+DROP COLUMN IF EXISTS group_chat_id;
+
+DROP TABLE data.groups;
+
+-- +goose StatementEnd
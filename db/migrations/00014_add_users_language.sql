@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE data.users
+ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE data.users
+DROP COLUMN IF EXISTS language;
+
+-- +goose StatementEnd
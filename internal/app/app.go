@@ -19,6 +19,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/vedomirr/remindista/pkg/psql"
+	"github.com/vedomirr/remindista/pkg/render"
 
 	"github.com/vedomirr/d"
 	"github.com/vedomirr/e"
@@ -47,6 +48,8 @@ type App struct {
 	server *http.Server
 	http   *httpv1.HttpController
 
+	telegram *tg.Telegram
+
 	updater service
 	worker  service
 }
@@ -83,12 +86,25 @@ func (a *App) Run() (exitCode int) {
 
 	go d.Run(a.config.Debug.ServerAddr)
 
+	// updates are posted to the webhook only while it's set, setting it again on restart is harmless
+	if err := a.telegram.SetWebhook(); err != nil {
+		a.logger.Error(e.Wrap("failed to set telegram webhook", err).Error())
+		return exitStatusFailed
+	}
+
 	select {
 	case err = <-a.errChan:
 		a.logger.Error(e.Wrap("fatal error, service shutdown", err).Error())
 		exitCode = exitStatusFailed
 	case <-ctx.Done():
 		a.logger.Info("service shutdown")
+
+		// the only instance is gone, telegram keeps pending updates until the webhook is set or polled again
+		if a.config.TG.Updates == "webhook" {
+			if err := a.telegram.DeleteWebhook(); err != nil {
+				a.logger.Error(e.Wrap("failed to delete telegram webhook", err).Error())
+			}
+		}
 	}
 
 	return exitStatusOk
@@ -122,14 +138,39 @@ func (a *App) init() (err error) {
 	}
 
 	// telegram entity
-	telegram, err := tg.NewTelegram(a.config.TG.Token)
+	renderer, err := render.ByName(a.config.TG.Format)
 	if err != nil {
 		return e.Wrap("failed to init telegram", err)
 	}
+
+	var tgOpts []tg.TelegramOption
+	switch a.config.TG.Updates {
+	case "polling":
+	case "webhook":
+		tgOpts = append(tgOpts, tg.WithWebhook(a.config.TG.WebhookUrl, a.config.TG.WebhookSecret))
+	default:
+		return e.Wrap("failed to init telegram", fmt.Errorf("unknown updates mode %q", a.config.TG.Updates))
+	}
+
+	telegram, err := tg.NewTelegram(a.config.TG.Token, renderer, tgOpts...)
+	if err != nil {
+		return e.Wrap("failed to init telegram", err)
+	}
+	a.telegram = telegram
+
+	// polling takes updates over from a webhook only when asked, before the updater starts polling
+	if a.config.TG.Updates == "polling" && a.config.TG.DeleteWebhook {
+		if err := telegram.DeleteWebhook(); err != nil {
+			return e.Wrap("failed to delete telegram webhook", err)
+		}
+	}
 	repo := repository.NewPostgresDB(pool)
 
-	a.updater = updater.NewUpdater(telegram, repo)
-	a.worker = worker.NewWorker(telegram, repo, a.config.Worker.Interval)
+	a.updater = updater.NewUpdater(telegram, repo, a.config.Calendar.BaseUrl, a.config.Chat.IdleTimeout, a.config.Trash.Retention, a.config.Share.Secret)
+	a.worker = worker.NewWorker(telegram, repo, a.config.Worker.Interval, a.config.Trash.Retention)
+
+	// http controller, read responder is not used by any handler yet
+	a.http = httpv1.NewHttpController(nil, repo, a.logger)
 
 	// http server
 	a.server = &http.Server{
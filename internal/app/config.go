@@ -14,6 +14,10 @@ type (
 		TG
 		Worker
 		PG
+		Calendar
+		Chat
+		Trash
+		Share
 	}
 
 	Target struct {
@@ -30,12 +34,42 @@ type (
 
 	TG struct {
 		Token string `env:"TG_TOKEN" env-default:"7769410503:AAEmqePfLePAEU7OCjI38x75mnb4M-7bNGs"`
+		// Format messages are sent in: markdownv2, html or plain
+		Format string `env:"TG_FORMAT" env-default:"markdownv2"`
+		// Updates are received by long `polling` or on `webhook` served by the api server.
+		// Either way only one instance may run, chat sessions and the worker live in its memory
+		Updates string `env:"TG_UPDATES" env-default:"polling"`
+		// DeleteWebhook unregisters the webhook on start when updates are polled, they can't be while it's set.
+		// It's only needed if the webhook instance didn't shut down gracefully, it unregisters the webhook itself otherwise
+		DeleteWebhook bool `env:"TG_DELETE_WEBHOOK" env-default:"false"`
+		// WebhookUrl is the public https url telegram posts updates to, its path is served by the api server
+		WebhookUrl string `env:"TG_WEBHOOK_URL"`
+		// WebhookSecret is checked in X-Telegram-Bot-Api-Secret-Token header of the posted updates
+		WebhookSecret string `env:"TG_WEBHOOK_SECRET"`
 	}
 
 	Worker struct {
 		Interval time.Duration `env:"WORKER_INTERVAL" env-default:"30s"`
 	}
 
+	Chat struct {
+		IdleTimeout time.Duration `env:"CHAT_IDLE_TIMEOUT" env-default:"15m"`
+	}
+
+	Trash struct {
+		Retention time.Duration `env:"TRASH_RETENTION" env-default:"720h"`
+	}
+
+	Share struct {
+		// Secret signs deep links saving shared reminders, anyone knowing it can copy any reminder.
+		// Rotating it invalidates every link shared before
+		Secret string `env:"SHARE_SECRET" env-required:"true"`
+	}
+
+	Calendar struct {
+		BaseUrl string `env:"CALENDAR_BASE_URL" env-default:"http://localhost:8888"`
+	}
+
 	PG struct {
 		Host          string `env:"PG_HOST" env-default:"postgres"`
 		Port          string `env:"PG_PORT" env-default:"5432"`
@@ -14,14 +14,23 @@ func (a *App) routes() *chi.Mux {
 
 	r.Use(middleware.Recoverer)
 	r.Use(a.zapLogger)
-	r.Use(a.rateLimiter)
-	r.Use(a.requestsCounter)
 
-	r.Get("/hello", a.http.HelloWorld)
+	// telegram posts all updates from a few addresses, so they're checked by the secret instead of rate limited
+	if path := a.telegram.WebhookPath(); path != "" {
+		r.With(a.requestsCounter).Post(path, a.telegram.WebhookHandler().ServeHTTP)
+	}
 
-	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL(fmt.Sprintf("http://localhost:%s/swagger/doc.json", strings.Split(a.config.Target.Addr, ":")[1])),
-	))
+	r.Group(func(r chi.Router) {
+		r.Use(a.rateLimiter)
+		r.Use(a.requestsCounter)
+
+		r.Get("/hello", a.http.HelloWorld)
+		r.Get("/calendar/{token}.ics", a.http.Calendar)
+
+		r.Get("/swagger/*", httpSwagger.Handler(
+			httpSwagger.URL(fmt.Sprintf("http://localhost:%s/swagger/doc.json", strings.Split(a.config.Target.Addr, ":")[1])),
+		))
+	})
 
 	return r
 }
@@ -0,0 +1,87 @@
+package httpv1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vedomirr/remindista/pkg/ical"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	calendarHorizon       = 30 * 24 * time.Hour
+	calendarEventsPerRmd  = 10
+	calendarEventDuration = 15 * time.Minute
+)
+
+// Calendar
+// @Summary iCalendar feed of upcoming reminders
+// @Description Returns upcoming deliveries of the user's reminders, the token is issued by the /calendar bot command
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "secret calendar token"
+// @Success 200 {string} string "VCALENDAR"
+// @Failure 404 {string} string "Not found"
+// @Router /calendar/{token}.ics [get]
+func (c *HttpController) Calendar(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	user, err := c.db.GetUserByCalendarToken(r.Context(), token)
+	if err != nil {
+		c.logger.Error("failed to get user by calendar token", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	} else if user.Id == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	rmds, err := c.db.GetRemindersByUserId(r.Context(), user.Id)
+	if err != nil {
+		c.logger.Error("failed to get reminders", zap.Int("user_id", user.Id), zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	now := user.Time()
+	cal := ical.Calendar{
+		ProductId: "-//remindista//reminders//EN",
+		Name:      "Remindista",
+		Location:  user.Location,
+	}
+
+	for _, rmd := range rmds {
+		var categories []string
+		if rmd.Tag != "" {
+			categories = []string{rmd.Tag}
+		}
+
+		for _, delivery := range rmd.UpcomingDeliveries(now.Add(calendarHorizon), calendarEventsPerRmd) {
+			cal.Events = append(cal.Events, ical.Event{
+				// occurrence keeps its uid across feed refreshes as long as its time stays the same
+				Uid:         fmt.Sprintf("%d-%d@remindista", rmd.Id, delivery.Unix()),
+				Summary:     rmd.Text,
+				Description: rmd.Prompt,
+				Categories:  categories,
+				Start:       delivery,
+				Duration:    calendarEventDuration,
+				Modified:    now,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="remindista.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+
+	if err := cal.Write(w); err != nil {
+		c.logger.Error("failed to write calendar", zap.Int("user_id", user.Id), zap.Error(err))
+	}
+}
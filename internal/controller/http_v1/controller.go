@@ -10,12 +10,14 @@ import (
 
 type HttpController struct {
 	rr     *rr.ReadResponder
+	db     repository
 	logger *zap.Logger
 }
 
-func NewHttpController(rr *rr.ReadResponder, logger *zap.Logger) *HttpController {
+func NewHttpController(rr *rr.ReadResponder, db repository, logger *zap.Logger) *HttpController {
 	return &HttpController{
 		rr:     rr,
+		db:     db,
 		logger: logger,
 	}
 }
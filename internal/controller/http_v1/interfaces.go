@@ -0,0 +1,13 @@
+package httpv1
+
+import (
+	"context"
+
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+)
+
+type repository interface {
+	GetUserByCalendarToken(ctx context.Context, token string) (user u.User, err error)
+	GetRemindersByUserId(ctx context.Context, userId int) (rmds []r.Reminder, err error)
+}
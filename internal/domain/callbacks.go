@@ -5,4 +5,17 @@ const (
 	CallbackUpdate            = ":update"
 	CallbackIncreaseFrequency = ":increase_frequency"
 	CallbackDecreaseFrequency = ":decrease_frequency"
+	CallbackEditText          = ":edit_text"
+	CallbackEditTag           = ":edit_tag"
+	CallbackEditPrompt        = ":edit_prompt"
+	CallbackEditFrequency     = ":edit_freq"
+	CallbackEditWindow        = ":edit_window"
+	CallbackUndoDelete        = ":undo_delete"
+	CallbackRestore           = ":restore"
+	CallbackPurge             = ":purge"
+	CallbackListPage          = ":list_page"
+	CallbackOpenCard          = ":open_card"
+	CallbackNoop              = ":noop"
+	CallbackPause             = ":pause"
+	CallbackResume            = ":resume"
 )
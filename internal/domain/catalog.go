@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// date layouts replies embed, they're translated like replies are
+const (
+	LayoutDateTime        = "on Jan _2 2006 at 15:04:05"
+	LayoutDateTimeMinutes = "on Jan _2 2006 at 15:04"
+)
+
+// catalog translates replies, toasts and button labels keyed by their english text,
+// english is the source language and needs no entry, see catalog_ru.go
+var catalog = map[string]map[string]string{
+	"ru": ru,
+}
+
+// Language picks supported language by telegram language_code like `ru` or `en-US`, others get the default one
+func Language(code string) string {
+	code, _, _ = strings.Cut(strings.ToLower(code), "-")
+	for _, lang := range Languages {
+		if lang == code {
+			return lang
+		}
+	}
+	return Languages[0]
+}
+
+// languageNames are shown to users in their own language
+var languageNames = map[string]string{
+	"en": "English",
+	"ru": "Русский",
+}
+
+// LanguageName is the name of supported language as its speakers write it
+func LanguageName(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// Tr translates english reply into the language, formatted replies are translated before formatting.
+// Anything without translation, including text already translated, stays as it is
+func Tr(lang, s string) string {
+	if t, ok := catalog[lang][s]; ok {
+		return t
+	}
+	return s
+}
+
+// TrKeyboard translates button labels, values stay as they are as buttons send them as input
+func TrKeyboard(lang string, kb Keyboard) Keyboard {
+	if catalog[lang] == nil || kb == nil {
+		return kb
+	}
+
+	translated := make(Keyboard, len(kb))
+	for i, row := range kb {
+		translated[i] = make([]Item, len(row))
+		for j, item := range row {
+			translated[i][j] = Item{Key: Tr(lang, item.Key), Val: item.Val}
+		}
+	}
+
+	return translated
+}
+
+// plural forms of units by language, in the order pluralForm picks them
+var plurals = map[string]map[string][]string{
+	"en": {
+		"minute": {"minute", "minutes"},
+		"hour":   {"hour", "hours"},
+		"day":    {"day", "days"},
+	},
+	"ru": {
+		"minute": {"минуту", "минуты", "минут"},
+		"hour":   {"час", "часа", "часов"},
+		"day":    {"день", "дня", "дней"},
+	},
+}
+
+// Plural is n followed by the unit in its plural form, like `1 day`, `2 days` or `5 дней`
+func Plural(lang string, n int, unit string) string {
+	forms, ok := plurals[lang][unit]
+	if !ok {
+		forms = plurals[Languages[0]][unit]
+	}
+	return fmt.Sprintf("%d %s", n, forms[pluralForm(lang, n)])
+}
+
+// pluralForm is one and other in english, one, few and many in russian
+func pluralForm(lang string, n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	switch lang {
+	case "ru":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		default:
+			return 2
+		}
+	default:
+		if n == 1 {
+			return 0
+		}
+		return 1
+	}
+}
+
+// month names by language as dates read in it, english ones come from the layout
+var months = map[string][12]string{
+	"ru": {"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+}
+
+// Date formats time in the layout translated into the language, translated layouts spell months as `January`
+func Date(lang string, t time.Time, layout string) string {
+	s := t.Format(Tr(lang, layout))
+	if names, ok := months[lang]; ok {
+		s = strings.Replace(s, t.Format("January"), names[t.Month()-1], 1)
+	}
+	return s
+}
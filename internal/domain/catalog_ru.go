@@ -0,0 +1,215 @@
+package domain
+
+// ru keeps commands, keywords and frequency examples in english as they are what the bot understands
+var ru = map[string]string{
+	// general replies
+	ReplyStart:         "Привет, %s\\!\nЭтот бот присылает напоминания в случайное время\\.",
+	ReplyCreateNewUser: "Привет, %s\\! Давайте настроим профиль\\.",
+	ReplyHelpHeader:    "Вот команды, которыми можно пользоваться:\n",
+	ReplyHelpFooter: "Наберите `@` и имя бота в любом чате, чтобы найти свои напоминания и поделиться ими\\.\n" +
+		"Ответьте на присланное напоминание `tag \\#go`, `every 3 days` или новым `текст :: вопрос`, чтобы изменить его\\.\n" +
+		"В многошаговых диалогах `back` возвращает на предыдущий шаг, а `cancel` прекращает диалог\\.",
+	ReplyUnkonwCommand:   "Неизвестная команда 🤨\\.",
+	ReplyFailedFindUser:  "Профиль пока не настроен 😕\\.\nНастройте его командой /update\\_user\\.",
+	ReplyCancel:          "Отменено 👌",
+	ReplyDone:            "Готово ✅",
+	ReplyYesNo:           "Ответьте __yes__ или __no__\\.",
+	ReplyCannotSkip:      "Этот шаг нельзя пропустить\\.",
+	ReplyCannotGoBack:    "Это первый шаг, возвращаться некуда\\.",
+	ReplySessionTimedOut: "Время диалога истекло ⌛ Начните заново с команды из /help\\.",
+
+	// error replies
+	ReplyErrorGettingReminder:  "Не удалось получить напоминания 😢: %w",
+	ReplyErrorCreatingReminder: "Не удалось создать напоминание 😐: %w",
+	ReplyErrorUpdatingReminder: "Не удалось обновить напоминание 😐: %w",
+	ReplyErrorDeletingReminder: "Не удалось удалить напоминания 😐: %w\\.",
+	ReplyErrorParsingLocation:  "Не удалось распознать часовой пояс 😢: %w\\. Попробуйте ещё раз\\.",
+	ReplyErrorParsingFrequency: "Не удалось разобрать частоту 😐: %w\\. Попробуете ещё раз\\?",
+	ReplyErrorParsingId:        "Не удалось разобрать номер напоминания 😢: %w\\. Попробуйте ещё раз\\.",
+	ReplyErrorParsingQuickAdd:  "Не удалось прочитать напоминание 😐: %w\\. Исправьте и отправьте `/add` снова или просто `/add`, чтобы добавить его по шагам\\.",
+	ReplyErrorParsingTag:       "Не удалось задать тег 😢: %w\\. Попробуйте ещё раз\\.",
+	ReplyErrorParsingTime:      "Не удалось задать время 😢: %w\\. Попробуйте ещё раз\\.",
+	ReplyErrorParsingWindow:    "Не удалось задать окно доставки 😢: %w\\. Попробуйте ещё раз\\.",
+	ReplyErrorCreatingUser:     "Не удалось создать профиль 😢: %w",
+	ReplyErrorUpdatingUser:     "Не удалось обновить профиль 😢: %w",
+	ReplyErrorReadingFile:      "Не удалось прочитать файл 😢: %w\\. Попробуете другой\\?",
+	ReplyErrorImporting:        "Не удалось импортировать карточки 😢: %w\\. Попробуете другой файл\\?",
+	ReplyErrorExporting:        "Не удалось экспортировать напоминания 😢: %w",
+	ReplyErrorCalendarLink:     "Не удалось настроить ссылку на календарь 😢: %w",
+	ReplyErrorReplyEdit:        "Не удалось изменить напоминание 😐: %w\\. Ответьте `tag \\#go`, `every 3 days` или новым текстом\\.",
+	ReplyErrorParsingLanguage:  "Не удалось выбрать язык 😢: %w\\. Попробуйте ещё раз\\.",
+
+	// f-strings
+	ReplyReminderSet:             "Напоминание создано\\. Следующее — _%s_\\.",
+	ReplySetLocation:             "Укажите часовой пояс или отправьте `skip`, чтобы оставить _%s_\\.",
+	ReplySetWindowFloor:          "Укажите, не раньше какого времени присылать напоминания, или отправьте `skip`, чтобы оставить _%s_\\.",
+	ReplySetWindowCeil:           "Укажите, не позже какого времени присылать напоминания, или отправьте `skip`, чтобы оставить _%s_\\.",
+	ReplySetLanguage:             "Выберите язык общения или отправьте `skip`, чтобы оставить _%s_\\.",
+	ReplyEvery:                   "раз в %s",
+	ReplyDeletedMultiple:         "Удалено напоминаний: %d ✅",
+	ReplyUpdateReminderText:      "Отправьте новый текст напоминания или `skip`, чтобы оставить:\n\n_%s_",
+	ReplyUpdateReminderTag:       "Укажите новый тег или отправьте `skip`, чтобы оставить _%s_\\.",
+	ReplyUpdateReminderFrequency: "Укажите новую частоту или оставьте _%s_\\.",
+	ReplyUpdateReminderPrompt:    "Отправьте новую подсказку или `skip`, чтобы оставить:\n\n_%s_",
+	ReplyImportFound:             "Новых карточек: %d, уже импортированных: %d\\. Укажите частоту для новых напоминаний\\. Например:\n2 days\n1 hour\n45 minutes",
+	ReplyImported:                "Импортировано новых напоминаний: %d, обновлено: %d ✅",
+	ReplyImportedPartially:       "Импортировано новых напоминаний: %d, обновлено: %d, с ошибкой: %d 😐",
+	ReplyExported:                "Экспортировано напоминаний: %d ✅",
+	ReplySetBulkFrequency:        "Получено напоминаний: %d\\. Укажите частоту для всех сразу\\. Например:\n2 days\n1 hour\n45 minutes",
+	ReplyBulkCreated:             "Создано %d из %d напоминаний\\.",
+	ReplyBulkEntryCreated:        "\n✅ %s",
+	ReplyBulkEntryFailed:         "\n❌ запись %d: %s",
+	ReplyCalendarLink:            "Подпишитесь на эту ссылку в календаре, чтобы видеть ближайшие напоминания:\n\n`%s`\n\nНикому её не показывайте\\. Кнопка *Новая ссылка* отзовёт эту и выдаст другую\\.",
+	ReplyCalendarLinkRotated:     "Старая ссылка отозвана\\. Новая:\n\n`%s`",
+	ReplyEditReminder:            "%s\n\nЧто изменить\\?",
+	ReplyEditReminderText:        "Отправьте новый текст напоминания\\. Сейчас он такой:\n\n_%s_",
+	ReplyEditReminderTag:         "Отправьте новый тег или `no\\_tag`, чтобы убрать его\\. Сейчас это _%s_\\.",
+	ReplyEditReminderPrompt:      "Отправьте новую подсказку или `no\\_prompt`, чтобы убрать её\\. Сейчас она такая:\n\n_%s_",
+	ReplyEditReminderFrequency:   "Отправьте новую частоту, сейчас это _%s_\\. Например:\n2 days\n1 hour\n45 minutes",
+	ReplyEditReminderWindow:      "Отправьте окно доставки этого напоминания, например `9:00\\-18:00`, или `default`, чтобы следовать настройкам профиля\\. Сейчас это _%s_\\.",
+	ReplyReminderUpdated:         "Напоминание обновлено ✅ Следующее — _%s_\\.",
+	ReplyReminderReplyEdited:     "%s\n\nОбновлено ✅",
+	ReplyTrash:                   "Удалённые напоминания хранятся %d дн\\. Восстановите нужные или удалите их насовсем:",
+	ReplyTrashItem:               "%s\n_удалено %s_",
+	ReplyRestored:                "Восстановлено напоминаний: %d ♻️",
+	ReplyTagActions:              "*%s*: напоминаний — %d\\. Что сделать\\?",
+	ReplyTagRenamed:              "Перенесено напоминаний: %d, новый тег — *%s* ✅",
+	ReplyTagsMerged:              "Объединено напоминаний: %d, общий тег — *%s* ✅",
+	ReplyTagUpdated:              "Обновлено напоминаний: %d ✅",
+	ReplyTagPaused:               "Обновлено напоминаний: %d ✅ Приостановленные отмечены ⏸ в /list\\.",
+	ReplyListPage:                "*%s* \\(%d\\)\n%s\n\nНажмите на номер, чтобы открыть карточку\\.",
+	ReplySearchFound:             "Найдено напоминаний: %d, сначала самые подходящие:",
+	ReplySharedSaved:             "%s\n\nСохранено в ваши напоминания ✅ Следующее — _%s_\\.",
+
+	// other replies
+	ReplySetReminderText:      "Отправьте текст напоминания\\.",
+	ReplySetReminderTag:       "Укажите тег напоминания или отправьте `skip`\\.",
+	ReplySetReminderPrompt:    "Отправьте подсказку к напоминанию или `skip`\\.",
+	ReplySetReminderFrequency: "Укажите частоту напоминания\\. Например:\n2 days\n1 hour\n45 minutes",
+
+	ReplyUserUpdated: "Профиль обновлён\\. Изменить настройки можно командой /update\\_user\\.",
+
+	ReplyListReminders:      "Выберите тег или отправьте его название, чтобы увидеть напоминания с ним\\. `all` покажет все напоминания, `cancel` — выход\\.",
+	ReplyNoReminders:        "Напоминаний нет\\. Создайте первое командой /add\\.",
+	ReplyNoRemindersWithTag: "Напоминаний не нашлось\\. Попробуйте другой тег или покажите все\\.",
+	ReplyAllReminders:       "Все напоминания",
+	ReplyListAnotherTag:     "Выберите другой тег, покажите все напоминания или отправьте `cancel`, чтобы выйти\\.",
+
+	ReplyModes:              "Отправьте `id`, `tag` или `all`, чтобы выбрать, что удалять\\. Выйти можно командой `cancel`\\.",
+	ReplySetMode:            "Удалять можно по номеру, по тегу или все сразу\\. Отправьте `id`, `tag` или `all`, чтобы выбрать, что удалять\\. Выйти можно командой `cancel`\\.",
+	ReplySendId:             "Отправьте номер напоминания, он выглядит так: `#12`\\. Его видно на карточках и в /list\\.",
+	ReplyNoSuchId:           "Напоминания с таким номером нет\\. Попробуете другой\\?",
+	ReplyDeleteMore:         "Удалить ещё\\?",
+	ReplySendTag:            "Выберите или отправьте тег, который нужно очистить\\.",
+	ReplyNoSuchTag:          "Напоминаний с таким тегом нет\\. Попробуете другой\\?",
+	ReplyConfirmDeletingAll: "Точно удалить все напоминания\\? Ответьте yes или no\\.",
+
+	ReplyNoPromt:      "\\(без подсказки\\)",
+	ReplyNoTag:        "без тега",
+	ReplyNoWindow:     "как в профиле",
+	ReplyLanguageAuto: "как в Telegram",
+	ReplyCardPaused:   "на паузе",
+	ReplyCardDeleted:  "удалено",
+
+	ReplyeConfirmDelete: "Точно удалить это напоминание\\? Ответьте yes или no\\.",
+
+	ReplyTrashEmpty: "Корзина пуста 🗑",
+	ReplyPurged:     "Напоминание удалено насовсем 🔥",
+	ReplyNotInTrash: "Этого напоминания уже нет в корзине\\.",
+
+	ReplyTags:         "Ваши теги и число напоминаний под каждым\\. Выберите тег, чтобы управлять им\\.",
+	ReplyRenameTag:    "Отправьте новое название тега\\. Если такой тег уже есть, напоминания объединятся\\.",
+	ReplyMergeTag:     "Выберите тег, с которым объединить\\.",
+	ReplySameTag:      "Это тот же тег\\. Отправьте другой\\.",
+	ReplyTagFrequency: "Отправьте частоту для всех напоминаний с этим тегом\\. Например:\n2 days\n1 hour\n45 minutes",
+	ReplyTagWindow:    "Отправьте окно доставки для всех напоминаний с этим тегом, например `9:00\\-18:00`, или `default`, чтобы следовать настройкам профиля\\.",
+	ReplySearchQuery:  "Отправьте слова, которые нужно найти в напоминаниях\\.",
+	ReplyNothingFound: "Ничего не нашлось 🤷 Попробуйте другие слова или отправьте `cancel`\\.",
+
+	ReplySharedNotFound: "Это напоминание больше недоступно 😕",
+	ReplySaveAfterSetup: "Когда профиль будет настроен, откройте ссылку ещё раз, чтобы сохранить напоминание\\.",
+
+	ReplySendImportFile: "Отправьте файл для импорта:\n" +
+		"• Anki `.apkg` — лицевая сторона станет текстом напоминания, оборот — подсказкой, а колода — тегом\\.\n" +
+		"• Markdown `.md` или `.zip` хранилища — каждый заголовок или блок `Q:`/`A:` станет напоминанием, тег из front matter или папка — тегом\\. " +
+		"Повторный импорт тех же заметок обновит напоминания, а не создаст копии\\.",
+	ReplyUnsupportedFile: "Такие файлы не поддерживаются\\. Отправьте Anki `.apkg`, markdown `.md` или `.zip` хранилища\\.",
+	ReplyNoCardsFound:    "В этом файле нет карточек\\. Попробуете другой\\?",
+	ReplyFileAlreadySent: "Файл уже получен\\. Укажите частоту или отправьте `cancel`\\.",
+	ReplySendFile:        "Пожалуйста, отправьте файл\\.",
+	ReplyUnexpectedFile:  "Сейчас файл не нужен 🤨",
+
+	ReplySendBulkReminders: "Отправьте напоминания одним сообщением, по одному на строку\\. Многострочные напоминания разделяйте строкой `---`\\. Формат:\n\n`text #tag :: prompt`\n\nТег и подсказка необязательны\\.",
+	ReplyNoBulkEntries:     "В сообщении не нашлось напоминаний\\. Попробуете ещё раз\\?",
+	ReplyFailedToSave:      "не удалось сохранить",
+	ReplyExportTag:         "Отправьте название тега или `no\\_tag`, чтобы экспортировать напоминания с тегом\\. `all` экспортирует все, `cancel` — выход\\.",
+
+	// group chat replies
+	ReplyGroupHelpHeader:   "Напоминания, добавленные здесь, приходят в эту группу\\. Команды пишутся одной строкой, например `/add Standup notes every 1 day #team` или `/delete #3`\\.\n\n",
+	ReplyGroupHelpFooter:   "Добавлять и удалять напоминания группы могут только её администраторы\\. Всё остальное — в личном чате со мной\\.",
+	ReplyGroupAdminsOnly:   "Напоминаниями группы управляют только администраторы 🙅",
+	ReplyGroupNeedsProfile: "Сначала запустите меня в личном чате: напоминания группы следуют вашему часовому поясу и окну доставки\\.",
+	ReplyGroupPrivateOnly:  "Эта команда работает в личном чате со мной\\.",
+	ReplyGroupAddUsage:     "Отправьте напоминание одной строкой, например `/add Standup notes every 1 day #team`\\.",
+	ReplyGroupDeleteUsage:  "Отправьте номер напоминания, например `/delete #3`\\.",
+	ReplyGroupReminderSet:  "Напоминание для группы создано\\. Следующее — _%s_\\.",
+
+	// toasts, plain text
+	ToastFrequencyUpdated: "Новая частота: %s",
+	ToastMaximumFrequency: "Реже уже нельзя: раз в год",
+	ToastMinimumFrequency: "Чаще уже нельзя: раз в минуту",
+	ToastPaused:           "На паузе ⏸ Не придёт, пока не возобновите",
+	ToastResumed:          "Возобновлено ▶️ Следующее — %s",
+	ToastRestored:         "Восстановлено напоминаний: %d ♻️",
+	ToastPurged:           "Удалено насовсем 🔥",
+	ToastNotInTrash:       "Этого напоминания уже нет в корзине",
+	ToastNoSuchReminder:   "Напоминание не найдено",
+	ToastPrivateOnly:      "Управлять напоминаниями можно в личном чате со мной",
+
+	// buttons
+	"Cancel":               "Отмена",
+	"Skip":                 "Пропустить",
+	"Back":                 "Назад",
+	"Add":                  "Добавить",
+	"Valid locations":      "Часовые пояса",
+	"ID":                   "Номер",
+	"Tag":                  "Тег",
+	"All":                  "Все",
+	"All (%d)":             "Все (%d)",
+	"Yes":                  "Да",
+	"No":                   "Нет",
+	"No tag":               "Без тега",
+	"No tag (%d)":          "Без тега (%d)",
+	"No prompt":            "Без подсказки",
+	"Default":              "Как в профиле",
+	"Done":                 "Готово",
+	"New link":             "Новая ссылка",
+	"Rename":               "Переименовать",
+	"Merge into…":          "Объединить с…",
+	"Pause all":            "Пауза для всех",
+	"Resume all":           "Возобновить все",
+	"Frequency":            "Частота",
+	"Window":               "Окно",
+	"As in Telegram":       "Как в Telegram",
+	"Pause":                "Пауза",
+	"Resume":               "Возобновить",
+	"Delete":               "Удалить",
+	"Edit":                 "Изменить",
+	"Freq ×2":              "Чаще ×2",
+	"Freq ÷2":              "Реже ÷2",
+	"Text":                 "Текст",
+	"Prompt":               "Подсказка",
+	"Undo":                 "Вернуть",
+	"Restore":              "Восстановить",
+	"Purge":                "Удалить насовсем",
+	"Save to my reminders": "Сохранить себе",
+	"◀ Prev":               "◀ Назад",
+	"Next ▶":               "Далее ▶",
+	"Next due":             "Ближайшие",
+	"A–Z":                  "А–Я",
+	"Newest":               "Новые",
+
+	// dates
+	LayoutDateTime:        "2 January 2006 в 15:04:05",
+	LayoutDateTimeMinutes: "2 January 2006 в 15:04",
+}
@@ -0,0 +1,223 @@
+package domain
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// untranslated are the same in every language
+var untranslated = map[string]bool{
+	"ReplyUnknown": true,
+	"English":      true,
+	"Русский":      true,
+}
+
+var reVerb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalog_Replies(t *testing.T) {
+	replies := parseConsts(t, "reply_messages.go")
+
+	for _, lang := range Languages[1:] {
+		for name, en := range replies {
+			if untranslated[name] {
+				continue
+			}
+
+			tr, ok := catalog[lang][en]
+			if !ok {
+				t.Errorf("%s has no %q translation", name, lang)
+				continue
+			}
+
+			if got, want := reVerb.FindAllString(tr, -1), reVerb.FindAllString(en, -1); strings.Join(got, "") != strings.Join(want, "") {
+				t.Errorf("%s in %q formats %v, want %v", name, lang, got, want)
+			}
+
+			// toasts are plain text
+			if !strings.HasPrefix(name, "Toast") {
+				if char, ok := unescapedMdV2(tr); ok {
+					t.Errorf("%s in %q has unescaped %q: %q", name, lang, char, tr)
+				}
+			}
+		}
+	}
+
+	for name, en := range replies {
+		if char, ok := unescapedMdV2(en); ok && !strings.HasPrefix(name, "Toast") {
+			t.Errorf("%s has unescaped %q: %q", name, char, en)
+		}
+	}
+}
+
+func TestCatalog_Buttons(t *testing.T) {
+	for _, label := range parseKeys(t, "keyboards.go") {
+		if untranslated[label] || !strings.ContainsFunc(label, unicode.IsLetter) {
+			continue
+		}
+
+		for _, lang := range Languages[1:] {
+			if _, ok := catalog[lang][label]; !ok {
+				t.Errorf("button %q has no %q translation", label, lang)
+			}
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	testCases := []struct {
+		lang string
+		n    int
+		unit string
+		want string
+	}{
+		{"en", 1, "day", "1 day"},
+		{"en", 2, "day", "2 days"},
+		{"en", 11, "hour", "11 hours"},
+		{"ru", 1, "day", "1 день"},
+		{"ru", 2, "day", "2 дня"},
+		{"ru", 5, "day", "5 дней"},
+		{"ru", 11, "minute", "11 минут"},
+		{"ru", 21, "hour", "21 час"},
+		{"ru", 22, "minute", "22 минуты"},
+		{"ru", 112, "day", "112 дней"},
+		{"de", 2, "day", "2 days"},
+	}
+
+	for _, tc := range testCases {
+		if got := Plural(tc.lang, tc.n, tc.unit); got != tc.want {
+			t.Errorf("Plural(%q, %d, %q) = %q, want %q", tc.lang, tc.n, tc.unit, got, tc.want)
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	date := time.Date(2025, time.March, 5, 9, 30, 15, 0, time.UTC)
+
+	testCases := []struct {
+		lang   string
+		layout string
+		want   string
+	}{
+		{"en", LayoutDateTime, "on Mar  5 2025 at 09:30:15"},
+		{"en", LayoutDateTimeMinutes, "on Mar  5 2025 at 09:30"},
+		{"ru", LayoutDateTime, "5 марта 2025 в 09:30:15"},
+		{"ru", LayoutDateTimeMinutes, "5 марта 2025 в 09:30"},
+	}
+
+	for _, tc := range testCases {
+		if got := Date(tc.lang, date, tc.layout); got != tc.want {
+			t.Errorf("Date(%q, %q) = %q, want %q", tc.lang, tc.layout, got, tc.want)
+		}
+		if char, ok := unescapedMdV2(Date(tc.lang, date, tc.layout)); ok {
+			t.Errorf("Date(%q, %q) has unescaped %q", tc.lang, tc.layout, char)
+		}
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for code, want := range map[string]string{"ru": "ru", "ru-RU": "ru", "en-US": "en", "de": "en", "": "en"} {
+		if got := Language(code); got != want {
+			t.Errorf("Language(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+// unescapedMdV2 finds reserved character outside of code that isn't escaped, markup characters are allowed
+func unescapedMdV2(s string) (string, bool) {
+	inCode := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '`':
+			inCode = !inCode
+		case !inCode && strings.IndexByte(".!-=+#>{}()[]|", c) >= 0:
+			return string(c), true
+		}
+	}
+	return "", false
+}
+
+// parseConsts reads string constants of the file, concatenated ones included
+func parseConsts(t *testing.T, file string) map[string]string {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consts := make(map[string]string)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				consts[name.Name] = evalString(t, vs.Values[i], consts)
+			}
+		}
+	}
+
+	return consts
+}
+
+func evalString(t *testing.T, expr ast.Expr, consts map[string]string) string {
+	t.Helper()
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		s, err := strconv.Unquote(e.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	case *ast.BinaryExpr:
+		return evalString(t, e.X, consts) + evalString(t, e.Y, consts)
+	case *ast.Ident:
+		return consts[e.Name]
+	default:
+		t.Fatalf("unexpected constant expression %T", expr)
+		return ""
+	}
+}
+
+// parseKeys reads button labels of the file, they are the first field of Item literals
+func parseKeys(t *testing.T, file string) []string {
+	t.Helper()
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok || len(lit.Elts) != 2 {
+			return true
+		}
+
+		key := lit.Elts[0]
+		if kv, ok := key.(*ast.KeyValueExpr); ok {
+			key = kv.Value
+		}
+
+		if basic, ok := key.(*ast.BasicLit); ok && basic.Kind == token.STRING {
+			keys = append(keys, evalString(t, basic, nil))
+		}
+		return true
+	})
+
+	return keys
+}
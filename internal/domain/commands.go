@@ -1,11 +1,180 @@
 package domain
 
+import (
+	"strings"
+
+	"github.com/vedomirr/remindista/pkg/render"
+)
+
 const (
 	CmdStart      = "/start"
 	CmdHelp       = "/help"
 	CmdUpdateUser = "/update_user"
 	CmdAdd        = "/add"
+	CmdAddBulk    = "/add_bulk"
 	CmdList       = "/list"
 	CmdDelete     = "/delete"
 	CmdUpdate     = "/update"
+	CmdImport     = "/import"
+	CmdExport     = "/export"
+	CmdCalendar   = "/calendar"
+	CmdTrash      = "/trash"
+	CmdSearch     = "/search"
+	CmdTags       = "/tags"
+)
+
+// Languages command descriptions are registered in, the first one is the default for everyone else
+var Languages = []string{"en", "ru"}
+
+// CommandScope tells where the command is offered in the menu
+type CommandScope int
+
+const (
+	ScopePrivate    CommandScope = 1 << iota // private chat with the bot
+	ScopeGroup                               // any group member
+	ScopeGroupAdmin                          // group administrators, they don't get group commands unless listed too
+
+	ScopeEveryone = ScopePrivate | ScopeGroup | ScopeGroupAdmin
 )
+
+// Scopes are registered one by one, the order is the one used for the menus
+var Scopes = []CommandScope{ScopePrivate, ScopeGroup, ScopeGroupAdmin}
+
+type Command struct {
+	Name  string
+	Scope CommandScope
+
+	// Description is plain text by language, see Languages
+	Description map[string]string
+
+	// Usage is MarkdownV2 example following the description in /help by language
+	Usage map[string]string
+}
+
+// Commands is the registry both the bot menu and /help are built from, in the order they are shown
+var Commands = []Command{
+	{Name: CmdStart, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Remindista startup",
+		"ru": "Запуск Remindista",
+	}},
+	{Name: CmdHelp, Scope: ScopeEveryone, Description: map[string]string{
+		"en": "Get instructions on how to use Remindista",
+		"ru": "Как пользоваться Remindista",
+	}},
+	{Name: CmdUpdateUser, Scope: ScopePrivate, Description: map[string]string{
+		"en": "User profile settings",
+		"ru": "Настройки профиля",
+	}},
+	{Name: CmdAdd, Scope: ScopePrivate | ScopeGroupAdmin, Description: map[string]string{
+		"en": "Add new reminder",
+		"ru": "Добавить напоминание",
+	}, Usage: map[string]string{
+		"en": ", or in one line like `/add Learn window functions every 3 days #sql`",
+		"ru": ", или одной строкой, например `/add Learn window functions every 3 days #sql`",
+	}},
+	{Name: CmdAddBulk, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Add many reminders with one message",
+		"ru": "Добавить много напоминаний одним сообщением",
+	}},
+	{Name: CmdList, Scope: ScopeEveryone, Description: map[string]string{
+		"en": "List reminders",
+		"ru": "Список напоминаний",
+	}, Usage: map[string]string{
+		"en": ", `/list #sql` shows one tag",
+		"ru": ", `/list #sql` покажет один тег",
+	}},
+	{Name: CmdTags, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Browse tags, rename or merge them, pause or change frequency of a whole tag",
+		"ru": "Теги: переименовать, объединить, поставить на паузу или сменить частоту",
+	}},
+	{Name: CmdSearch, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Search reminders by words from text, prompt or tag",
+		"ru": "Поиск напоминаний по тексту, подсказке или тегу",
+	}, Usage: map[string]string{
+		"en": ", like `/search sql join`",
+		"ru": ", например `/search sql join`",
+	}},
+	{Name: CmdUpdate, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Edit reminder parameters",
+		"ru": "Изменить напоминание",
+	}, Usage: map[string]string{
+		"en": ", `/update #12` opens the reminder right away",
+		"ru": ", `/update #12` сразу откроет напоминание",
+	}},
+	{Name: CmdDelete, Scope: ScopePrivate | ScopeGroupAdmin, Description: map[string]string{
+		"en": "Delete reminder(s)",
+		"ru": "Удалить напоминания",
+	}, Usage: map[string]string{
+		"en": ", like `/delete #12`, `/delete #sql` or `/delete all`",
+		"ru": ", например `/delete #12`, `/delete #sql` или `/delete all`",
+	}},
+	{Name: CmdImport, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Import reminders from Anki or Markdown",
+		"ru": "Импорт из Anki или Markdown",
+	}},
+	{Name: CmdExport, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Export reminders to Anki",
+		"ru": "Экспорт в Anki",
+	}},
+	{Name: CmdCalendar, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Get calendar feed link",
+		"ru": "Ссылка на календарь",
+	}},
+	{Name: CmdTrash, Scope: ScopePrivate, Description: map[string]string{
+		"en": "Restore recently deleted reminders",
+		"ru": "Восстановить удалённые напоминания",
+	}},
+}
+
+// CommandsIn lists commands offered in the scope
+func CommandsIn(scope CommandScope) []Command {
+	cmds := make([]Command, 0, len(Commands))
+	for _, cmd := range Commands {
+		if cmd.Scope&scope != 0 {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// DescriptionIn falls back to the default language if there is no translation
+func (c Command) DescriptionIn(lang string) string {
+	if desc, ok := c.Description[lang]; ok {
+		return desc
+	}
+	return c.Description[Languages[0]]
+}
+
+// UsageIn falls back to the default language if there is no translation, commands without usage have none
+func (c Command) UsageIn(lang string) string {
+	if usage, ok := c.Usage[lang]; ok {
+		return usage
+	}
+	return c.Usage[Languages[0]]
+}
+
+// Help is /help reply in the language listing private chat commands
+func Help(lang string) string {
+	var str strings.Builder
+	str.WriteString(Tr(lang, ReplyHelpHeader))
+
+	for _, cmd := range CommandsIn(ScopePrivate) {
+		str.WriteString(render.EscapeMarkdownV2(cmd.Name) + " — " + render.EscapeMarkdownV2(cmd.DescriptionIn(lang)) + cmd.UsageIn(lang) + "\n")
+	}
+
+	str.WriteString("\n" + Tr(lang, ReplyHelpFooter))
+	return str.String()
+}
+
+// GroupHelp is /help reply in groups listing commands of group members and admins
+func GroupHelp(lang string) string {
+	var str strings.Builder
+	str.WriteString(Tr(lang, ReplyGroupHelpHeader))
+
+	for _, cmd := range CommandsIn(ScopeGroup | ScopeGroupAdmin) {
+		str.WriteString(render.EscapeMarkdownV2(cmd.Name) + " — " + render.EscapeMarkdownV2(cmd.DescriptionIn(lang)) + "\n")
+	}
+
+	str.WriteString("\n" + Tr(lang, ReplyGroupHelpFooter))
+	return str.String()
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vedomirr/remindista/pkg/render"
+)
+
+func TestCommands_Descriptions(t *testing.T) {
+	for _, cmd := range Commands {
+		for _, lang := range Languages {
+			// bot api limits descriptions to 256 characters
+			if desc := cmd.Description[lang]; desc == "" || len([]rune(desc)) > 256 {
+				t.Errorf("%s description in %q = %q", cmd.Name, lang, desc)
+			}
+
+			if _, ok := cmd.Usage[lang]; len(cmd.Usage) > 0 && !ok {
+				t.Errorf("%s usage isn't translated into %q", cmd.Name, lang)
+			}
+		}
+
+		if cmd.Scope == 0 {
+			t.Errorf("%s isn't offered in any scope", cmd.Name)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	for _, lang := range Languages {
+		help := Help(lang)
+
+		for _, cmd := range CommandsIn(ScopePrivate) {
+			if !strings.Contains(help, render.EscapeMarkdownV2(cmd.Name)+" — "+render.EscapeMarkdownV2(cmd.DescriptionIn(lang))) {
+				t.Errorf("%q help doesn't mention %s", lang, cmd.Name)
+			}
+		}
+
+		if strings.Contains(help, "update_user") {
+			t.Errorf("%q help isn't escaped: %q", lang, help)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// Conversation is a snapshot of an unfinished chat flow, kept to resume it after restart
+type Conversation struct {
+	ChatId     int64
+	TelegramId int64
+	Flow       string
+	Step       string
+	History    []string
+	State      []byte
+	UpdatedAt  time.Time
+}
@@ -3,6 +3,14 @@ package domain
 import "errors"
 
 var (
-	ErrorInvalidCallback = errors.New("invalid callback")
-	ErrorShortTag        = errors.New("tag should be at least 2 characters long")
+	ErrorInvalidCallback  = errors.New("invalid callback")
+	ErrorShortTag         = errors.New("tag should be at least 2 characters long")
+	ErrorEmptyText        = errors.New("reminder text is empty")
+	ErrorInvalidNumber    = errors.New("reminder number should look like #12")
+	ErrorInvalidTag       = errors.New("tag should be a single word")
+	ErrorInvalidFrequency = errors.New("frequency should be N minutes, hours or days")
+
+	// reminder reads and writes are scoped by owner, see repository
+	ErrorReminderNotFound = errors.New("reminder not found")
+	ErrorForbidden        = errors.New("reminder belongs to another user")
 )
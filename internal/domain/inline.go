@@ -0,0 +1,15 @@
+package domain
+
+// InlineQuery is a `@bot query` typed by user in any chat
+type InlineQuery struct {
+	Id string
+}
+
+// InlineResult is a card offered in reply to an inline query, Text is sent to the chat when picked
+type InlineResult struct {
+	Id          string
+	Title       string
+	Description string
+	Text        string
+	Keyboard
+}
@@ -32,7 +32,20 @@ var (
 		[]Item{{Key: "20:30", Val: "20:30"}, {Key: "23:30", Val: "23:30"}},
 		[]Item{{Key: "Cancel", Val: "cancel"}, {Key: "Skip", Val: "skip"}},
 	}
-	KbListReminders = Keyboard{[]Item{{"Cancel", "cancel"}, {"No tag", "no_tag"}, {"All", "all"}}}
-	KbSetMode       = Keyboard{[]Item{{"Cancel", "cancel"}, {"ID", "id"}, {"Tag", "tag"}, {"All", "all"}}}
-	KbYesNo         = Keyboard{[]Item{{"Yes", "yes"}, {"No", "no"}}}
+	KbSetMode    = Keyboard{[]Item{{"Cancel", "cancel"}, {"ID", "id"}, {"Tag", "tag"}, {"All", "all"}}}
+	KbYesNo      = Keyboard{[]Item{{"Yes", "yes"}, {"No", "no"}}}
+	KbEditTag    = Keyboard{[]Item{{"Cancel", "cancel"}, {"No tag", "no_tag"}}}
+	KbEditPrompt = Keyboard{[]Item{{"Cancel", "cancel"}, {"No prompt", "no_prompt"}}}
+	KbEditWindow = Keyboard{[]Item{{"Cancel", "cancel"}, {"Default", "default"}}}
+	KbExport     = Keyboard{[]Item{{"Cancel", "cancel"}, {"No tag", "no_tag"}, {"All", "all"}}}
+	KbCalendar   = Keyboard{[]Item{{"Done", "done"}, {"New link", "rotate"}}}
+	KbLanguages  = Keyboard{
+		[]Item{{"English", "en"}, {"Русский", "ru"}},
+		[]Item{{"Cancel", "cancel"}, {"Skip", "skip"}, {"As in Telegram", "auto"}},
+	}
+	KbTagActions = Keyboard{
+		[]Item{{"Rename", "rename"}, {"Merge into…", "merge"}},
+		[]Item{{"Pause all", "pause"}, {"Resume all", "resume"}},
+		[]Item{{"Cancel", "cancel"}, {"Frequency", "frequency"}, {"Window", "window"}},
+	}
 )
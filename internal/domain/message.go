@@ -4,10 +4,44 @@ type Item struct{ Key, Val string }
 
 type Keyboard [][]Item
 
+// WithBack returns a copy of the keyboard with a Back button prepended to its last row
+func (k Keyboard) WithBack() Keyboard {
+	back := Item{Key: "Back", Val: "back"}
+
+	if len(k) == 0 {
+		return Keyboard{{back}}
+	}
+
+	kb := make(Keyboard, len(k))
+	copy(kb, k)
+	kb[len(kb)-1] = append([]Item{back}, k[len(k)-1]...)
+
+	return kb
+}
+
+type Document struct {
+	Id   string
+	Name string
+	Data []byte
+}
+
 type Message struct {
 	ChatId     int64
+	ChatType   string // private, group, supergroup or channel, as telegram names them
+	ChatTitle  string
+	MessageId  int    // message the callback came from, outgoing messages with it edit that message
+	ReplyTo    int    // message the incoming one replies to
+	CallbackId string // pressed button, outgoing messages with it answer the callback showing text as a toast
 	TelegramId int64
 	UserName   string
+	Lang       string // sender's language, replies are translated into it when sent
 	Text       string
 	Keyboard
+	Document *Document
+	Inline   *InlineQuery
+}
+
+// IsGroup tells the message came from a group chat, those don't run chat sessions
+func (m Message) IsGroup() bool {
+	return m.ChatType == "group" || m.ChatType == "supergroup"
 }
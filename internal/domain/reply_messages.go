@@ -16,21 +16,19 @@ Custom emoji entities can only be used by bots that purchased additional usernam
 const (
 	ReplyStart         = "Hello %s\\!\nThis bot sends you reminders in a randomly timed manner\\."
 	ReplyCreateNewUser = "Hello %s\\! Let's set up your user profile\\."
-	ReplyHelp          = "Here are some commands use can use:\n" +
-		"/start — Remindista startup\n" +
-		"/help — Get instructions on how to use Remindista\n" +
-		"/update\\_user — User profile settings\n" +
-		"/add — Add new reminder\n" +
-		"/list — List reminders\n" +
-		"/update — Edit reminder parameters\n" +
-		"/delete — Delete reminder\\(s\\)"
-	ReplyUnkonwCommand  = "Unknown command 🤨\\."
-	ReplyFailedFindUser = "Sorry, user profile data is not set 😕\\.\nUse /update_user update your profile\\."
-	ReplyUnknown        = `🤨`
-	ReplyCancel         = "Cancelled 👌"
-	ReplyDone           = "Done ✅"
-	ReplyYesNo          = `Say __yes__ or __no__\\.`
-	ReplyCannotSkip     = "Sorry, cannot skip this step\\."
+	ReplyHelpHeader    = "Here are some commands use can use:\n"
+	ReplyHelpFooter    = "Type `@` and the bot's name in any chat to search your reminders and share them\\.\n" +
+		"Reply to a delivered reminder with `tag \\#go`, `every 3 days` or a new `text :: prompt` to change it\\.\n" +
+		"During multi\\-step dialogs use `back` to return to the previous step or `cancel` to stop\\."
+	ReplyUnkonwCommand   = "Unknown command 🤨\\."
+	ReplyFailedFindUser  = "Sorry, user profile data is not set 😕\\.\nUse /update_user update your profile\\."
+	ReplyUnknown         = `🤨`
+	ReplyCancel          = "Cancelled 👌"
+	ReplyDone            = "Done ✅"
+	ReplyYesNo           = "Say __yes__ or __no__\\."
+	ReplyCannotSkip      = "Sorry, cannot skip this step\\."
+	ReplyCannotGoBack    = "This is the first step, there is nowhere to go back\\."
+	ReplySessionTimedOut = "Session timed out ⌛ Start over with a command from /help\\."
 )
 
 // error replies
@@ -41,11 +39,19 @@ const (
 	ReplyErrorDeletingReminder = "Failed to delete reminder\\(s\\) 😐: %w\\."
 	ReplyErrorParsingLocation  = "Couldn't recognize location 😢: %w\\. Try one more time please\\."
 	ReplyErrorParsingFrequency = "Couldn't parse frequency 😐: %w\\. Try again\\?"
-	ReplyErrorParsingId        = "Couldn't parse reminder id 😢: %w\\. Try one more time\\."
+	ReplyErrorParsingId        = "Couldn't parse reminder number 😢: %w\\. Try one more time\\."
+	ReplyErrorParsingQuickAdd  = "Couldn't read the reminder 😐: %w\\. Fix it and send `/add` again, or just `/add` to add it step by step\\."
 	ReplyErrorParsingTag       = "Couldn't set reminder's tag 😢: %w\\. Try one more time\\."
 	ReplyErrorParsingTime      = "Couldn't set time 😢: %w\\. Try one more time\\."
+	ReplyErrorParsingWindow    = "Couldn't set delivery window 😢: %w\\. Try one more time\\."
 	ReplyErrorCreatingUser     = "Couldn't create user 😢: %w"
 	ReplyErrorUpdatingUser     = "Couldn't update user 😢: %w"
+	ReplyErrorReadingFile      = "Couldn't read the file 😢: %w\\. Try another one\\?"
+	ReplyErrorImporting        = "Couldn't import cards 😢: %w\\. Try another file\\?"
+	ReplyErrorExporting        = "Couldn't export reminders 😢: %w"
+	ReplyErrorCalendarLink     = "Couldn't set up calendar link 😢: %w"
+	ReplyErrorReplyEdit        = "Couldn't update the reminder 😐: %w\\. Reply with `tag \\#go`, `every 3 days` or a new text\\."
+	ReplyErrorParsingLanguage  = "Couldn't set language 😢: %w\\. Try one more time\\."
 )
 
 // f-strings
@@ -54,14 +60,42 @@ const (
 	ReplySetLocation             = "Specify your location or send `skip` to leave _%s_\\."
 	ReplySetWindowFloor          = "Set the lower time boundary for your notifications or send `skip` to leave _%s_\\."
 	ReplySetWindowCeil           = "Set the upper time boundary for your notifications or send `skip` to leave _%s_\\."
+	ReplySetLanguage             = "Pick the language I should speak or send `skip` to leave _%s_\\."
+	ReplyEvery                   = "every %s"
 	ReplyDeletedMultiple         = "Deleted %d reminder\\(s\\) ✅"
 	ReplyUpdateReminderText      = "Send new reminder text or `skip` to keep:\n\n_%s_"
 	ReplyUpdateReminderTag       = "Specify new reminder's tag or send `skip` to keep _%s_\\."
 	ReplyUpdateReminderFrequency = "Set new reminder frequency or leave _%s_\\."
 	ReplyUpdateReminderPrompt    = "Send new prompt text or `skip` to keep:\n\n_%s_"
-	ReplyFrequencyUpdated        = "Reminder frequency updated\\. New frequency is _%s_\\."
-	ReplyMaximumFrequency        = "Frequency is at its maximum of once per year\\."
-	ReplyMinimumFrequency        = "Frequency is at its minimum of 1 minute\\."
+	ReplyImportFound             = "Found %d new card\\(s\\) and %d already imported\\. Specify frequency for the new reminders\\. Examples:\n2 days\n1 hour\n45 minutes"
+	ReplyImported                = "Imported %d new reminder\\(s\\), updated %d ✅"
+	ReplyImportedPartially       = "Imported %d new reminder\\(s\\), updated %d, %d failed 😐"
+	ReplyExported                = "Exported %d reminder\\(s\\) ✅"
+	ReplySetBulkFrequency        = "Got %d reminder\\(s\\)\\. Specify frequency for all of them\\. Examples:\n2 days\n1 hour\n45 minutes"
+	ReplyBulkCreated             = "Created %d of %d reminder\\(s\\)\\."
+	ReplyBulkEntryCreated        = "\n✅ %s"
+	ReplyBulkEntryFailed         = "\n❌ entry %d: %s"
+	ReplyCalendarLink            = "Subscribe to this link in your calendar app to see upcoming reminders:\n\n`%s`\n\nKeep it secret\\. Press *New link* to revoke it and get another one\\."
+	ReplyCalendarLinkRotated     = "Old link revoked\\. New link:\n\n`%s`"
+	ReplyEditReminder            = "%s\n\nWhat would you like to change\\?"
+	ReplyEditReminderText        = "Send new reminder text\\. Now it is:\n\n_%s_"
+	ReplyEditReminderTag         = "Send new tag, or `no\\_tag` to remove it\\. Now it is _%s_\\."
+	ReplyEditReminderPrompt      = "Send new prompt, or `no\\_prompt` to remove it\\. Now it is:\n\n_%s_"
+	ReplyEditReminderFrequency   = "Send new frequency, now it is _%s_\\. Examples:\n2 days\n1 hour\n45 minutes"
+	ReplyEditReminderWindow      = "Send the time window to deliver this reminder in, like `9:00\\-18:00`, or `default` to follow your profile settings\\. Now it is _%s_\\."
+	ReplyReminderUpdated         = "Reminder updated ✅ Next reminder is _%s_\\."
+	ReplyReminderReplyEdited     = "%s\n\nUpdated ✅"
+	ReplyTrash                   = "Deleted reminders are kept for %d day\\(s\\)\\. Restore the ones you need or purge them right away:"
+	ReplyTrashItem               = "%s\n_deleted %s_"
+	ReplyRestored                = "Restored %d reminder\\(s\\) ♻️"
+	ReplyTagActions              = "*%s* has %d reminder\\(s\\)\\. What would you like to do\\?"
+	ReplyTagRenamed              = "Moved %d reminder\\(s\\) to *%s* ✅"
+	ReplyTagsMerged              = "Merged %d reminder\\(s\\) into *%s* ✅"
+	ReplyTagUpdated              = "Updated %d reminder\\(s\\) ✅"
+	ReplyTagPaused               = "Updated %d reminder\\(s\\) ✅ Paused ones are marked with ⏸ in /list\\."
+	ReplyListPage                = "*%s* \\(%d\\)\n%s\n\nTap a number to open the card\\."
+	ReplySearchFound             = "Found %d reminder\\(s\\), best matches first:"
+	ReplySharedSaved             = "%s\n\nSaved to your reminders ✅ Next reminder is _%s_\\."
 )
 
 // other replies
@@ -73,21 +107,84 @@ const (
 
 	ReplyUserUpdated = "User profile updated\\. To change user setings, use /update\\_user\\."
 
-	ReplyListReminders      = "Send tag name or `no\\_tag` to list reminders by tag\\. Say `all` to list all reminders, or `cancel` to exit\\."
+	ReplyListReminders      = "Pick a tag or send its name to list reminders by tag\\. Say `all` to list all reminders, or `cancel` to exit\\."
 	ReplyNoReminders        = "No reminders found\\. Use /add to create a reminder\\."
 	ReplyNoRemindersWithTag = "No reminders found\\. Try another tag or list all reminders\\."
+	ReplyAllReminders       = "All reminders"
 	ReplyListAnotherTag     = "Specify another tag, list all reminders, or send `cancel` to exit\\."
 
 	ReplyModes              = "Send `id`, `tag`, or `all` to pick delete mode\\. To exit you can say `cancel`\\."
 	ReplySetMode            = "You can delete reminders by id, tag, or just delete all of them\\. " + ReplyModes
-	ReplySendId             = "Send rimender id, the one that looks like this: `0xfff`"
-	ReplyNoSuchId           = "Couldn't find reminder with this id\\. Try another one\\?"
+	ReplySendId             = "Send reminder number, the one that looks like this: `#12`\\. You can find it on reminder cards and in /list\\."
+	ReplyNoSuchId           = "Couldn't find reminder with this number\\. Try another one\\?"
 	ReplyDeleteMore         = "Delete more\\?"
-	ReplySendTag            = "Send the tag you want to clear\\."
+	ReplySendTag            = "Pick or send the tag you want to clear\\."
 	ReplyNoSuchTag          = "Couldn't find reminders with this tag\\. Try another one\\?"
 	ReplyConfirmDeletingAll = "Are you sure you want to delete all reminders\\? Answer yes or no\\."
 
-	ReplyNoPromt = "(no prompt)"
+	ReplyNoPromt      = "\\(no prompt\\)"
+	ReplyNoTag        = "no tag"
+	ReplyNoWindow     = "profile default"
+	ReplyLanguageAuto = "as in Telegram"
+	ReplyCardPaused   = "paused"
+	ReplyCardDeleted  = "deleted"
 
 	ReplyeConfirmDelete = "Are you sure you want to delete this reminder\\? Answer yes or no\\."
+
+	ReplyTrashEmpty = "Trash is empty 🗑"
+	ReplyPurged     = "Reminder deleted for good 🔥"
+	ReplyNotInTrash = "This reminder is not in the trash anymore\\."
+
+	ReplyTags         = "Your tags with the number of reminders under each\\. Pick one to manage it\\."
+	ReplyRenameTag    = "Send the new tag name\\. If you already have such a tag, reminders will be merged into it\\."
+	ReplyMergeTag     = "Pick the tag to merge into\\."
+	ReplySameTag      = "That's the same tag\\. Send another one\\."
+	ReplyTagFrequency = "Send the frequency for every reminder under this tag\\. Examples:\n2 days\n1 hour\n45 minutes"
+	ReplyTagWindow    = "Send the time window for every reminder under this tag, like `9:00\\-18:00`, or `default` to follow your profile settings\\."
+	ReplySearchQuery  = "Send words to look for in your reminders\\."
+	ReplyNothingFound = "Nothing found 🤷 Try other words or send `cancel`\\."
+
+	ReplySharedNotFound = "This shared reminder is not available anymore 😕"
+	ReplySaveAfterSetup = "Once your profile is set up, open the shared link again to save the reminder\\."
+
+	ReplySendImportFile = "Send a file to import:\n" +
+		"• Anki `.apkg` — front side becomes reminder text, back side becomes prompt and deck name becomes tag\\.\n" +
+		"• Markdown `.md` note or `.zip` of a vault — each heading or `Q:`/`A:` block becomes a reminder, front matter tag or folder becomes tag\\. " +
+		"Importing the same notes again updates reminders instead of duplicating them\\."
+	ReplyUnsupportedFile = "Sorry, this file type is not supported\\. Send an Anki `.apkg`, a markdown `.md` or a `.zip` vault\\."
+	ReplyNoCardsFound    = "No cards found in this file\\. Try another one\\?"
+	ReplyFileAlreadySent = "File is already received\\. Specify frequency or send `cancel`\\."
+	ReplySendFile        = "Please send a file\\."
+	ReplyUnexpectedFile  = "Not expecting a file right now 🤨"
+
+	ReplySendBulkReminders = "Send reminders in one message, one per line\\. To add multi\\-line reminders, separate them with a `---` line\\. Format:\n\n`text #tag :: prompt`\n\nTag and prompt are optional\\."
+	ReplyNoBulkEntries     = "Couldn't find any reminders in this message\\. Try again\\?"
+	ReplyFailedToSave      = "failed to save"
+	ReplyExportTag         = "Send tag name or `no\\_tag` to export reminders by tag\\. Say `all` to export all reminders, or `cancel` to exit\\."
+)
+
+// group chat replies, commands take their arguments there as group chats have no chat sessions
+const (
+	ReplyGroupHelpHeader   = "Reminders added here are sent to this group\\. Commands take everything in one line, like `/add Standup notes every 1 day #team` or `/delete #3`\\.\n\n"
+	ReplyGroupHelpFooter   = "Only group admins can add and delete group reminders\\. Everything else is in a private chat with me\\."
+	ReplyGroupAdminsOnly   = "Only group admins can manage group reminders 🙅"
+	ReplyGroupNeedsProfile = "Start me in a private chat first, group reminders follow your time zone and delivery window\\."
+	ReplyGroupPrivateOnly  = "This command works in a private chat with me\\."
+	ReplyGroupAddUsage     = "Send the reminder in one line, like `/add Standup notes every 1 day #team`\\."
+	ReplyGroupDeleteUsage  = "Send the reminder number, like `/delete #3`\\."
+	ReplyGroupReminderSet  = "Reminder set for this group\\. Next reminder is _%s_\\."
+)
+
+// callback answers shown as toasts, plain text
+const (
+	ToastFrequencyUpdated = "New frequency: %s"
+	ToastMaximumFrequency = "Frequency is at its maximum of once per year"
+	ToastMinimumFrequency = "Frequency is at its minimum of 1 minute"
+	ToastPaused           = "Paused ⏸ It won't be sent until you resume it"
+	ToastResumed          = "Resumed ▶️ Next reminder is %s"
+	ToastRestored         = "Restored %d reminder(s) ♻️"
+	ToastPurged           = "Deleted for good 🔥"
+	ToastNotInTrash       = "This reminder is not in the trash anymore"
+	ToastNoSuchReminder   = "Couldn't find this reminder"
+	ToastPrivateOnly      = "Open a private chat with me to manage reminders"
 )
@@ -0,0 +1,11 @@
+package group
+
+// Group is a group chat the bot was added to, its reminders are delivered there and managed by its admins
+type Group struct {
+	ChatId int64
+	Title  string
+}
+
+func NewGroup(chatId int64, title string) Group {
+	return Group{ChatId: chatId, Title: title}
+}
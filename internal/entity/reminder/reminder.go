@@ -4,26 +4,56 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vedomirr/remindista/internal/domain"
+	"github.com/vedomirr/remindista/pkg/render"
 )
 
+const promptSeparator = "::"
+
+var reInlineTag = regexp.MustCompile(`(?:^|\s)(#[\p{L}\p{N}_]+)`)
+
+// reInlineFrequency is `every 3 days` or `every hour` inside a quick add line
+var reInlineFrequency = regexp.MustCompile(`(?i)(?:^|\s)every\s+(?:(\d+)\s+)?(minutes?|hours?|days?)\b`)
+
+// reReplyTag is `tag #go`, `tag no_tag` or just `#go` replied to a delivered reminder
+var reReplyTag = regexp.MustCompile(`(?i)^(?:tag\s+(\S+)|(#[\p{L}\p{N}_]+))$`)
+
+// reReplyCommand is a reply starting like a tag or frequency command, it's never taken as a new text
+var reReplyCommand = regexp.MustCompile(`(?i)^(tag|every)(?:\s|$)`)
+
 type Reminder struct {
 	Id           int
 	UserId       int
+	Number       int // sequential per user, shown to user and used in callbacks instead of id
 	Text         string
 	Tag          string
 	Prompt       string
 	Frequency    time.Duration
 	NextReminder time.Time
+	SourceKey    string
+	DeletedAt    time.Time
+
+	// own delivery window as offsets from midnight, zero ceil means user's window applies
+	WindowFloor time.Duration
+	WindowCeil  time.Duration
+
+	// paused reminders are not delivered until resumed
+	IsActive bool
+
+	// group chat the reminder is delivered to, zero means owner's private chat
+	GroupChatId int64
 }
 
 type ReminderOption func(*Reminder)
 
 func NewReminder(opts ...ReminderOption) (r Reminder) {
+	r.IsActive = true
+
 	for _, opt := range opts {
 		opt(&r)
 	}
@@ -37,6 +67,18 @@ func WithUserId(id int) ReminderOption {
 	}
 }
 
+func WithGroupChatId(chatId int64) ReminderOption {
+	return func(r *Reminder) {
+		r.GroupChatId = chatId
+	}
+}
+
+func WithSourceKey(key string) ReminderOption {
+	return func(r *Reminder) {
+		r.SourceKey = key
+	}
+}
+
 func (r *Reminder) String() string {
 	var str strings.Builder
 	str.WriteString(r.Text + "\n")
@@ -49,77 +91,113 @@ func (r *Reminder) String() string {
 		str.WriteString(r.Prompt + "\n")
 	}
 
-	str.WriteString(r.FreqeuncyString() + "\n")
-	str.WriteString(r.IdToHex())
+	str.WriteString(r.FreqeuncyString(domain.Languages[0]) + "\n")
+	str.WriteString(r.NumberString())
 
 	return str.String()
 }
 
-func (r *Reminder) StringMdV2() string {
-	var str strings.Builder
-	str.WriteString(r.TextMdV2() + "\n")
+// StringMdV2 is the reminder card, frequency and status are in the language
+func (r *Reminder) StringMdV2(lang string) string {
+	return render.MarkdownV2{}.Render(r.Card(lang))
+}
+
+// Card is the reminder with its prompt hidden under spoiler, frequency and status are in the language
+func (r *Reminder) Card(lang string) render.Content {
+	return r.card(lang, render.Content{}.Text(r.Text), render.Content{}.Spoiler(r.Prompt))
+}
+
+// card lays out reminder card around text and prompt styled by the caller
+func (r *Reminder) card(lang string, text, prompt render.Content) render.Content {
+	c := text.Text("\n")
 
 	if r.Tag != "" {
-		str.WriteString(r.TagMdV2() + "\n")
+		c = c.Text(r.Tag + "\n")
 	}
 
 	if r.Prompt != "" {
-		str.WriteString("||" + r.PromptMdV2() + "||\n")
+		c = c.Append(prompt).Text("\n")
 	}
 
-	str.WriteString("`" + r.FreqeuncyString() + "`")
+	if r.Number > 0 {
+		c = c.Code(r.NumberString()).Text(" ")
+	}
 
-	return str.String()
+	c = c.Code(r.FreqeuncyString(lang))
+
+	if r.HasWindow() {
+		c = c.Text(" ").Code(r.WindowString())
+	}
+
+	if !r.IsActive {
+		c = c.Text(" ⏸ ").Italic(domain.Tr(lang, domain.ReplyCardPaused))
+	}
+
+	return c
+}
+
+// DeletedMdV2 is the card struck through once the reminder is deleted
+func (r *Reminder) DeletedMdV2(lang string) string {
+	return render.MarkdownV2{}.Render(r.Deleted(lang))
+}
+
+func (r *Reminder) Deleted(lang string) render.Content {
+	return render.Content{}.Text(r.NumberString() + " ").Strike(r.Text).Text("\n").Italic(domain.Tr(lang, domain.ReplyCardDeleted))
+}
+
+// RowMdV2 is a one-line summary of the reminder for lists
+func (r *Reminder) RowMdV2(lang string, titleLength int) string {
+	return render.MarkdownV2{}.Render(r.Row(lang, titleLength))
+}
+
+func (r *Reminder) Row(lang string, titleLength int) render.Content {
+	var c render.Content
+	if !r.IsActive {
+		c = c.Text("⏸ ")
+	}
+
+	c = c.Text(r.Title(titleLength))
+
+	if r.Tag != "" {
+		c = c.Text(" " + r.Tag)
+	}
+
+	return c.Text(" · ").Italic(r.FreqeuncyString(lang))
 }
 
 func (r *Reminder) TextMdV2() string {
-	return r.escapedMdV2(r.Text)
+	return render.EscapeMarkdownV2(r.Text)
 }
 
 func (r *Reminder) TagMdV2() string {
-	return r.escapedMdV2(r.Tag)
+	return render.EscapeMarkdownV2(r.Tag)
 }
 
 func (r *Reminder) PromptMdV2() string {
-	return r.escapedMdV2(r.Prompt)
+	return render.EscapeMarkdownV2(r.Prompt)
 }
 
-func (r *Reminder) FreqeuncyString() string {
-	var str strings.Builder
-
+// FreqeuncyString is like `every 2 days 3 hours` in the language, plural forms follow the numbers
+func (r *Reminder) FreqeuncyString(lang string) string {
 	days := int(r.Frequency / (time.Hour * 24))
-	if days > 0 {
-		if days == 1 {
-			str.WriteString("1 day ")
-		} else {
-			str.WriteString(fmt.Sprintf("%d days ", days))
-		}
-	}
-
 	hours := int((r.Frequency - time.Duration(days)*time.Hour*24).Hours())
-	if hours > 0 {
-		if hours == 1 {
-			str.WriteString("1 hour ")
-		} else {
-			str.WriteString(fmt.Sprintf("%d hours ", hours))
-		}
-	}
-
 	mins := int((r.Frequency - time.Duration(days)*time.Hour*24 - time.Duration(hours)*time.Hour).Minutes())
-	if mins > 0 {
-		if mins == 1 {
-			str.WriteString("1 minute")
-		} else {
-			str.WriteString(fmt.Sprintf("%d minutes", mins))
+
+	parts := make([]string, 0, 3)
+	for _, part := range []struct {
+		n    int
+		unit string
+	}{{days, "day"}, {hours, "hour"}, {mins, "minute"}} {
+		if part.n > 0 {
+			parts = append(parts, domain.Plural(lang, part.n, part.unit))
 		}
 	}
 
-	result := str.String()
-	if result != "" {
-		result = "every " + result
+	if len(parts) == 0 {
+		return ""
 	}
 
-	return result
+	return fmt.Sprintf(domain.Tr(lang, domain.ReplyEvery), strings.Join(parts, " "))
 }
 
 func (r *Reminder) SetTag(s string) error {
@@ -141,6 +219,110 @@ func (r *Reminder) SetTag(s string) error {
 	return nil
 }
 
+// ParseLine fills text, tag and prompt from a string like "text #tag :: prompt", tag and prompt are optional
+func (r *Reminder) ParseLine(s string) error {
+	text, prompt, _ := strings.Cut(s, promptSeparator)
+
+	if m := reInlineTag.FindStringSubmatchIndex(text); m != nil {
+		if err := r.SetTag(text[m[2]:m[3]]); err != nil {
+			return err
+		}
+		text = strings.TrimSpace(text[:m[2]]) + " " + strings.TrimSpace(text[m[3]:])
+	}
+
+	if r.Text = strings.TrimSpace(text); r.Text == "" {
+		return domain.ErrorEmptyText
+	}
+
+	r.Prompt = strings.TrimSpace(prompt)
+
+	return nil
+}
+
+// ParseQuickAdd reads one-line reminder like `Learn window functions every 3 days #sql`,
+// frequency stays zero if the line doesn't mention it
+func (r *Reminder) ParseQuickAdd(s string) error {
+	text, prompt, hasPrompt := strings.Cut(s, promptSeparator)
+
+	if m := reInlineFrequency.FindStringSubmatchIndex(text); m != nil {
+		n := "1"
+		if m[2] >= 0 {
+			n = text[m[2]:m[3]]
+		}
+		if err := r.SetFrequency(n + " " + text[m[4]:m[5]]); err != nil {
+			return err
+		}
+		text = strings.TrimSpace(text[:m[0]]) + " " + strings.TrimSpace(text[m[1]:])
+	}
+
+	if hasPrompt {
+		text += promptSeparator + prompt
+	}
+
+	return r.ParseLine(text)
+}
+
+// ApplyReply changes reminder by a reply to its delivered message:
+// `tag #go` or `#go` sets the tag, `every 3 days` the frequency, anything else is parsed as "text #tag :: prompt".
+// Prompt is kept if the reply doesn't have one
+func (r *Reminder) ApplyReply(s string) error {
+	s = strings.TrimSpace(s)
+
+	if m := reReplyTag.FindStringSubmatch(s); m != nil {
+		return r.SetTag(m[1] + m[2])
+	}
+
+	if m := reInlineFrequency.FindStringSubmatchIndex(s); m != nil && m[0] == 0 && m[1] == len(s) {
+		n := "1"
+		if m[2] >= 0 {
+			n = s[m[2]:m[3]]
+		}
+		return r.SetFrequency(n + " " + s[m[4]:m[5]])
+	}
+
+	if m := reReplyCommand.FindStringSubmatch(s); m != nil {
+		if strings.EqualFold(m[1], "tag") {
+			return domain.ErrorInvalidTag
+		}
+		return domain.ErrorInvalidFrequency
+	}
+
+	upd := *r
+	if err := upd.ParseLine(s); err != nil {
+		return err
+	}
+	if !strings.Contains(s, promptSeparator) {
+		upd.Prompt = r.Prompt
+	}
+	*r = upd
+
+	return nil
+}
+
+// Matches reports whether every word of the query is found in reminder's text, tag or prompt
+func (r *Reminder) Matches(query string) bool {
+	content := strings.ToLower(r.Text + " " + r.Tag + " " + r.Prompt)
+
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(content, word) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Title is the first line of reminder's text shortened to limit runes
+func (r *Reminder) Title(limit int) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(r.Text), "\n")
+
+	if runes := []rune(title); len(runes) > limit {
+		return strings.TrimSpace(string(runes[:limit-1])) + "…"
+	}
+
+	return title
+}
+
 func (r *Reminder) TagMatches(s string) bool {
 	if len(s) < 2 {
 		return false
@@ -157,29 +339,87 @@ func (r *Reminder) TagMatches(s string) bool {
 	return r.Tag == strings.ToLower(s)
 }
 
-func (r *Reminder) NextReminderString() string {
-	return r.NextReminder.Format("on Jan _2 2006 at 15:04:05")
+func (r *Reminder) HasWindow() bool {
+	return r.WindowCeil > 0
 }
 
-// TODO: remove this function
-func (r *Reminder) HexToId(s string) error {
-	if len(s) < 3 || s[:2] != "0x" {
-		return errors.New("Must be a hexadecimal number with leading '0x'")
+func (r *Reminder) WindowString() string {
+	if !r.HasWindow() {
+		return ""
 	}
+	return formatClock(r.WindowFloor) + "-" + formatClock(r.WindowCeil)
+}
+
+func (r *Reminder) WindowMdV2() string {
+	return render.EscapeMarkdownV2(r.WindowString())
+}
+
+// SetWindow parses own delivery window like "9:00-18:30", "default" resets it to user's window
+func (r *Reminder) SetWindow(s string) error {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "default" {
+		r.WindowFloor, r.WindowCeil = 0, 0
+		return nil
+	}
+
+	floorStr, ceilStr, ok := strings.Cut(strings.ReplaceAll(s, "–", "-"), "-")
+	if !ok {
+		return errors.New("start and end should be separated by a dash")
+	}
+
+	floor, err := parseClock(floorStr)
+	if err != nil {
+		return err
+	}
+
+	ceil, err := parseClock(ceilStr)
+	if err != nil {
+		return err
+	}
+
+	if floor >= ceil {
+		return errors.New("window start must be before its end")
+	}
+
+	r.WindowFloor, r.WindowCeil = floor, ceil
+
+	return nil
+}
 
-	i, err := strconv.ParseInt(s[2:], 16, 64)
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
 	if err != nil {
-		return fmt.Errorf("Failed to parse hex: %w", err)
+		return 0, errors.New("time should look like 9:30")
 	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func (r *Reminder) NextReminderString(lang string) string {
+	return domain.Date(lang, r.NextReminder, domain.LayoutDateTime)
+}
 
-	r.Id = int(i)
+func (r *Reminder) DeletedAtString(lang string) string {
+	return domain.Date(lang, r.DeletedAt, domain.LayoutDateTimeMinutes)
+}
+
+// SetNumber parses per-user reminder number written as `#12` or `12`
+func (r *Reminder) SetNumber(s string) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+	if err != nil || n <= 0 {
+		return domain.ErrorInvalidNumber
+	}
+
+	r.Number = n
 
 	return nil
 }
 
-// TODO: remove this function
-func (r *Reminder) IdToHex() string {
-	return fmt.Sprintf("%#x", r.Id)
+func (r *Reminder) NumberString() string {
+	return fmt.Sprintf("#%d", r.Number)
 }
 
 func (r *Reminder) SetFrequency(s string) error {
@@ -188,8 +428,9 @@ func (r *Reminder) SetFrequency(s string) error {
 		return fmt.Errorf("should be 2 parts, got: %v", parts)
 	}
 
+	// zero or negative frequency can't be scheduled, see RandomizedDuration
 	n, err := strconv.Atoi(parts[0])
-	if err != nil {
+	if err != nil || n <= 0 {
 		return fmt.Errorf("N should natrual number, got: %v", parts[0])
 	}
 
@@ -211,7 +452,12 @@ func (r *Reminder) SetFrequency(s string) error {
 	return nil
 }
 
+// UpdateNextReminder schedules the next delivery within user's window, or reminder's own one if set
 func (r *Reminder) UpdateNextReminder(userTime time.Time, floor, ceil time.Duration) {
+	if r.HasWindow() {
+		floor, ceil = r.WindowFloor, r.WindowCeil
+	}
+
 	next := userTime.Add(r.RandomizedDuration(r.Frequency))
 	nextTrunc := time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, next.Location())
 
@@ -228,7 +474,37 @@ func (r *Reminder) UpdateNextReminder(userTime time.Time, floor, ceil time.Durat
 	r.NextReminder = next
 }
 
+// Resume activates paused reminder and schedules it from the current time
+func (r *Reminder) Resume(userTime time.Time, floor, ceil time.Duration) {
+	r.IsActive = true
+	r.UpdateNextReminder(userTime, floor, ceil)
+}
+
+// UpcomingDeliveries projects deliveries up to the given time, only the first one is exact since the rest get randomized
+func (r *Reminder) UpcomingDeliveries(until time.Time, limit int) []time.Time {
+	if !r.IsActive {
+		return nil
+	}
+
+	deliveries := make([]time.Time, 0, limit)
+
+	for next := r.NextReminder; !next.After(until) && len(deliveries) < limit; next = next.Add(r.Frequency) {
+		deliveries = append(deliveries, next)
+
+		if r.Frequency <= 0 {
+			break
+		}
+	}
+
+	return deliveries
+}
+
 func (r *Reminder) RandomizedDuration(d time.Duration) time.Duration {
+	// nothing to randomize, rand.Int63n panics on it
+	if d <= 0 {
+		return d
+	}
+
 	x := d.Nanoseconds()      // toal duration in nanoseconds
 	x += rand.Int63n(x) - x/4 // randomized duration by quarter distance
 
@@ -236,25 +512,49 @@ func (r *Reminder) RandomizedDuration(d time.Duration) time.Duration {
 }
 
 func (r *Reminder) Keyboard() domain.Keyboard {
-	return domain.Keyboard{[]domain.Item{
-		{Key: "Delete", Val: fmt.Sprintf("%s %d", domain.CallbackDelete, r.Id)},
-		{Key: "Update", Val: fmt.Sprintf("%s %d", domain.CallbackUpdate, r.Id)},
-		{Key: "Freq ×2", Val: fmt.Sprintf("%s %d", domain.CallbackIncreaseFrequency, r.Id)},
-		{Key: "Freq ÷2", Val: fmt.Sprintf("%s %d", domain.CallbackDecreaseFrequency, r.Id)},
-	}}
-}
+	pause := domain.Item{Key: "Pause", Val: fmt.Sprintf("%s %d", domain.CallbackPause, r.Number)}
+	if !r.IsActive {
+		pause = domain.Item{Key: "Resume", Val: fmt.Sprintf("%s %d", domain.CallbackResume, r.Number)}
+	}
 
-/*
-Inside pre and code entities, all '`' and '\' characters must be escaped with a preceding '\' character.
-Inside the (...) part of the inline link and custom emoji definition, all ')' and '\' must be escaped with a preceding '\' character.
-In all other places characters '_', '*', '[', ']', '(', ')', '~', '`', '>', '#', '+', '-', '=', '|', '{', '}', '.', '!' must be escaped with the preceding character '\'.
-*/
-func (r *Reminder) escapedMdV2(s string) string {
-	specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
+	return domain.Keyboard{
+		[]domain.Item{
+			{Key: "Delete", Val: fmt.Sprintf("%s %d", domain.CallbackDelete, r.Number)},
+			{Key: "Edit", Val: fmt.Sprintf("%s %d", domain.CallbackUpdate, r.Number)},
+			pause,
+		},
+		[]domain.Item{
+			{Key: "Freq ×2", Val: fmt.Sprintf("%s %d", domain.CallbackIncreaseFrequency, r.Number)},
+			{Key: "Freq ÷2", Val: fmt.Sprintf("%s %d", domain.CallbackDecreaseFrequency, r.Number)},
+		},
+	}
+}
 
-	for _, char := range specialChars {
-		s = strings.Replace(s, char, "\\"+char, -1)
+// EditKeyboard is the edit menu, each button changes a single field
+func (r *Reminder) EditKeyboard() domain.Keyboard {
+	return domain.Keyboard{
+		[]domain.Item{
+			{Key: "Text", Val: fmt.Sprintf("%s %d", domain.CallbackEditText, r.Number)},
+			{Key: "Tag", Val: fmt.Sprintf("%s %d", domain.CallbackEditTag, r.Number)},
+			{Key: "Prompt", Val: fmt.Sprintf("%s %d", domain.CallbackEditPrompt, r.Number)},
+		},
+		[]domain.Item{
+			{Key: "Frequency", Val: fmt.Sprintf("%s %d", domain.CallbackEditFrequency, r.Number)},
+			{Key: "Window", Val: fmt.Sprintf("%s %d", domain.CallbackEditWindow, r.Number)},
+		},
 	}
+}
 
-	return s
+// UndoKeyboard is attached to the delete confirmation, it restores everything deleted along with the reminder
+func (r *Reminder) UndoKeyboard() domain.Keyboard {
+	return domain.Keyboard{[]domain.Item{
+		{Key: "Undo", Val: fmt.Sprintf("%s %d", domain.CallbackUndoDelete, r.Number)},
+	}}
+}
+
+func (r *Reminder) TrashKeyboard() domain.Keyboard {
+	return domain.Keyboard{[]domain.Item{
+		{Key: "Restore", Val: fmt.Sprintf("%s %d", domain.CallbackRestore, r.Number)},
+		{Key: "Purge", Val: fmt.Sprintf("%s %d", domain.CallbackPurge, r.Number)},
+	}}
 }
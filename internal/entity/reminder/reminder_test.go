@@ -1,6 +1,11 @@
 package reminder
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+)
 
 func TestReminder_String(t *testing.T) {
 	// testCases := []struct {
@@ -12,9 +17,45 @@ func TestReminder_String(t *testing.T) {
 	// }
 }
 
-func TestReminder_StringMdV2(t *testing.T) {}
+func TestReminder_StringMdV2(t *testing.T) {
+	rmd := Reminder{
+		Number:      3,
+		Text:        `C:\new_dir (copy).txt`,
+		Tag:         "#win_32",
+		Prompt:      "use `dir /b`!",
+		Frequency:   48 * time.Hour,
+		WindowFloor: 9 * time.Hour,
+		WindowCeil:  18 * time.Hour,
+	}
+
+	want := "C:\\\\new\\_dir \\(copy\\)\\.txt\n\\#win\\_32\n||use \\`dir /b\\`\\!||\n`#3` `every 2 days` `09:00-18:00` ⏸ _paused_"
+	if got := rmd.StringMdV2("en"); got != want {
+		t.Errorf("StringMdV2() = %q, want %q", got, want)
+	}
+}
+
+func TestReminder_FrequencyString(t *testing.T) {
+	testCases := []struct {
+		lang      string
+		frequency time.Duration
+		want      string
+	}{
+		{"en", 24 * time.Hour, "every 1 day"},
+		{"en", 50*time.Hour + 5*time.Minute, "every 2 days 2 hours 5 minutes"},
+		{"ru", 24 * time.Hour, "раз в 1 день"},
+		{"ru", 3 * time.Hour, "раз в 3 часа"},
+		{"ru", 5 * 24 * time.Hour, "раз в 5 дней"},
+		{"ru", 21 * time.Minute, "раз в 21 минуту"},
+		{"en", 0, ""},
+	}
 
-func TestReminder_FrequencyString(t *testing.T) {}
+	for _, tc := range testCases {
+		rmd := Reminder{Frequency: tc.frequency}
+		if got := rmd.FreqeuncyString(tc.lang); got != tc.want {
+			t.Errorf("FreqeuncyString(%q) of %s = %q, want %q", tc.lang, tc.frequency, got, tc.want)
+		}
+	}
+}
 
 func TestReminder_SetTag(t *testing.T) {}
 
@@ -25,3 +66,249 @@ func TestReminder_NextReminderString(t *testing.T) {}
 func TestReminder_SetFrequency(t *testing.T) {}
 
 func TestReminder_UpdateNextReminder(t *testing.T) {}
+
+func TestReminder_ParseLine(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		wantText   string
+		wantTag    string
+		wantPrompt string
+		wantErr    bool
+	}{
+		{"text only", "Learn window functions", "Learn window functions", "", "", false},
+		{"tag at the end", "Learn window functions #SQL", "Learn window functions", "#sql", "", false},
+		{"tag in the middle", "Learn #sql window functions", "Learn window functions", "#sql", "", false},
+		{"prompt", "JOIN types #sql :: inner, left, right, full", "JOIN types", "#sql", "inner, left, right, full", false},
+		{"hash inside word", "C# basics", "C# basics", "", "", false},
+		{"multi-line", "first line\nsecond #go line\n:: answer", "first line\nsecond line", "#go", "answer", false},
+		{"empty text", "#sql :: prompt", "", "#sql", "prompt", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var r Reminder
+
+			err := r.ParseLine(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseLine() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if r.Text != tc.wantText || r.Tag != tc.wantTag || r.Prompt != tc.wantPrompt {
+				t.Errorf("ParseLine() = (%q, %q, %q), want (%q, %q, %q)", r.Text, r.Tag, r.Prompt, tc.wantText, tc.wantTag, tc.wantPrompt)
+			}
+		})
+	}
+}
+
+func TestReminder_SetWindow(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"hours", "9:00-18:00", "09:00-18:00", false},
+		{"spaces and dash", " 7:30 – 12:15 ", "07:30-12:15", false},
+		{"default", "default", "", false},
+		{"reversed", "18:00-9:00", "", true},
+		{"empty window", "9:00-9:00", "", true},
+		{"no dash", "9:00", "", true},
+		{"bad time", "9am-5pm", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewReminder()
+
+			err := r.SetWindow(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("SetWindow(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+
+			if got := r.WindowString(); got != tc.want {
+				t.Errorf("WindowString() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReminder_Matches(t *testing.T) {
+	r := Reminder{Text: "SQL JOIN types", Tag: "#sql", Prompt: "inner, left, right, full"}
+
+	testCases := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"join", true},
+		{"sql join", true},
+		{"#sql", true},
+		{"LEFT", true},
+		{"join #go", false},
+		{"postgres", false},
+	}
+
+	for _, tc := range testCases {
+		if got := r.Matches(tc.query); got != tc.want {
+			t.Errorf("Matches(%q) = %v, want %v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestReminder_Title(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"short", "Go channels", "Go channels"},
+		{"first line", "Go channels\nbuffered and not", "Go channels"},
+		{"long", "Горутины и каналы", "Горутины и…"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Reminder{Text: tc.text}
+			if got := r.Title(12); got != tc.want {
+				t.Errorf("Title(12) = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestReminder_Resume(t *testing.T) {
+	r := NewReminder()
+	r.Frequency = time.Hour
+	r.NextReminder = time.Now().Add(-48 * time.Hour)
+	r.IsActive = false
+
+	if got := r.UpcomingDeliveries(time.Now().Add(time.Hour), 10); len(got) != 0 {
+		t.Errorf("paused reminder deliveries = %v, want none", got)
+	}
+	if got := r.Keyboard()[0][2].Val; got != domain.CallbackResume+" 0" {
+		t.Errorf("paused reminder button = %q, want resume", got)
+	}
+
+	now := time.Now()
+	r.Resume(now, 0, 24*time.Hour-time.Minute)
+
+	if !r.IsActive || r.NextReminder.Before(now) {
+		t.Errorf("resumed reminder active = %v, next = %v, want active and scheduled after %v", r.IsActive, r.NextReminder, now)
+	}
+	if got := r.Keyboard()[0][2].Val; got != domain.CallbackPause+" 0" {
+		t.Errorf("active reminder button = %q, want pause", got)
+	}
+}
+
+func TestReminder_SetNumber(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"#12", 12, false},
+		{"7", 7, false},
+		{" #3 ", 3, false},
+		{"#0", 0, true},
+		{"-1", 0, true},
+		{"0xfff", 0, true},
+		{"twelve", 0, true},
+	}
+
+	for _, tc := range testCases {
+		r := NewReminder()
+
+		err := r.SetNumber(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("SetNumber(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+		}
+		if r.Number != tc.want {
+			t.Errorf("SetNumber(%q) number = %d, want %d", tc.input, r.Number, tc.want)
+		}
+	}
+}
+
+func TestReminder_ParseQuickAdd(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		wantText      string
+		wantTag       string
+		wantPrompt    string
+		wantFrequency time.Duration
+		wantErr       bool
+	}{
+		{"everything", "Learn window functions every 3 days #sql", "Learn window functions", "#sql", "", 72 * time.Hour, false},
+		{"no number", "Stretch every hour", "Stretch", "", "", time.Hour, false},
+		{"in the middle", "Review #go every 2 Days notes :: channels", "Review notes", "#go", "channels", 48 * time.Hour, false},
+		{"no frequency", "Learn window functions #sql", "Learn window functions", "#sql", "", 0, false},
+		{"word inside text", "everyday tasks", "everyday tasks", "", "", 0, false},
+		{"frequency only", "every 3 days", "", "", "", 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var r Reminder
+
+			err := r.ParseQuickAdd(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ParseQuickAdd() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			if r.Text != tc.wantText || r.Tag != tc.wantTag || r.Prompt != tc.wantPrompt || r.Frequency != tc.wantFrequency {
+				t.Errorf("ParseQuickAdd() = (%q, %q, %q, %v), want (%q, %q, %q, %v)", r.Text, r.Tag, r.Prompt, r.Frequency, tc.wantText, tc.wantTag, tc.wantPrompt, tc.wantFrequency)
+			}
+		})
+	}
+}
+
+func TestReminder_ApplyReply(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         string
+		wantText      string
+		wantTag       string
+		wantPrompt    string
+		wantFrequency time.Duration
+		wantErr       bool
+	}{
+		{"tag", "tag #Go", "Goroutines", "#go", "Why?", time.Hour, false},
+		{"tag without hash", "tag go", "Goroutines", "#go", "Why?", time.Hour, false},
+		{"hash only", "#go", "Goroutines", "#go", "Why?", time.Hour, false},
+		{"clear tag", "tag no_tag", "Goroutines", "", "Why?", time.Hour, false},
+		{"frequency", "every 3 days", "Goroutines", "#sql", "Why?", 72 * time.Hour, false},
+		{"frequency without number", "Every day", "Goroutines", "#sql", "Why?", 24 * time.Hour, false},
+		{"new body", "Goroutines are cheap, every day counts", "Goroutines are cheap, every day counts", "#sql", "Why?", time.Hour, false},
+		{"new body with prompt", "Channels :: When to close?", "Channels", "#sql", "When to close?", time.Hour, false},
+		{"new body with tag", "Channels #Go", "Channels", "#go", "Why?", time.Hour, false},
+		{"short tag", "tag g", "Goroutines", "#sql", "Why?", time.Hour, true},
+		{"tag alone", "tag", "Goroutines", "#sql", "Why?", time.Hour, true},
+		{"tag of two words", "tag go sql", "Goroutines", "#sql", "Why?", time.Hour, true},
+		{"unknown frequency unit", "every 2 weeks", "Goroutines", "#sql", "Why?", time.Hour, true},
+		{"zero frequency", "every 0 days", "Goroutines", "#sql", "Why?", time.Hour, true},
+		{"every alone", "every", "Goroutines", "#sql", "Why?", time.Hour, true},
+		{"empty body", " :: When to close?", "Goroutines", "#sql", "Why?", time.Hour, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := Reminder{Text: "Goroutines", Tag: "#sql", Prompt: "Why?", Frequency: time.Hour}
+
+			err := r.ApplyReply(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ApplyReply(%q) error = %v, wantErr %v", tc.input, err, tc.wantErr)
+			}
+
+			if r.Text != tc.wantText || r.Tag != tc.wantTag || r.Prompt != tc.wantPrompt || r.Frequency != tc.wantFrequency {
+				t.Errorf("ApplyReply(%q) = (%q, %q, %q, %v), want (%q, %q, %q, %v)", tc.input, r.Text, r.Tag, r.Prompt, r.Frequency, tc.wantText, tc.wantTag, tc.wantPrompt, tc.wantFrequency)
+			}
+		})
+	}
+}
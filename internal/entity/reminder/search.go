@@ -0,0 +1,47 @@
+package reminder
+
+import (
+	"strings"
+
+	"github.com/vedomirr/remindista/pkg/render"
+)
+
+// markers wrapping matched words in search headlines, they can't appear in user's text
+const (
+	HighlightStart = "\x01"
+	HighlightStop  = "\x02"
+)
+
+// SearchHit is a reminder found by full-text search, headlines are text and prompt with matches marked
+type SearchHit struct {
+	Reminder
+	TextHeadline   string
+	PromptHeadline string
+	Rank           float32
+}
+
+// StringMdV2 is the reminder card with matched words in bold
+func (h *SearchHit) StringMdV2(lang string) string {
+	return render.MarkdownV2{}.Render(h.Card(lang))
+}
+
+func (h *SearchHit) Card(lang string) render.Content {
+	return h.card(lang, highlighted(h.TextHeadline, 0), highlighted(h.PromptHeadline, render.Spoiler))
+}
+
+// highlighted is the headline in the style with matched words also in bold
+func highlighted(headline string, style render.Style) (c render.Content) {
+	for {
+		before, rest, found := strings.Cut(headline, HighlightStart)
+		c = c.Add(style, before)
+		if !found {
+			break
+		}
+
+		match, after, _ := strings.Cut(rest, HighlightStop)
+		c = c.Add(style|render.Bold, match)
+		headline = after
+	}
+
+	return c
+}
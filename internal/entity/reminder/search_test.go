@@ -0,0 +1,50 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearchHit_StringMdV2(t *testing.T) {
+	mark := func(s string) string { return HighlightStart + s + HighlightStop }
+	freq := "\n`every 1 hour`"
+
+	testCases := []struct {
+		name string
+		hit  SearchHit
+		want string
+	}{
+		{
+			name: "no matches",
+			hit:  SearchHit{Reminder: Reminder{Text: "a.b", Frequency: time.Hour, IsActive: true}, TextHeadline: "a.b"},
+			want: "a\\.b" + freq,
+		},
+		{
+			name: "text and prompt",
+			hit: SearchHit{
+				Reminder:       Reminder{Text: "SQL joins", Tag: "#sql", Prompt: "left (outer) join", Frequency: time.Hour, IsActive: true},
+				TextHeadline:   "SQL " + mark("joins"),
+				PromptHeadline: "left (outer) " + mark("join"),
+			},
+			want: "SQL *joins*\n\\#sql\n||left \\(outer\\) *join*||" + freq,
+		},
+		{
+			name: "paused",
+			hit:  SearchHit{Reminder: Reminder{Text: "go", Frequency: time.Hour}, TextHeadline: mark("go")},
+			want: "*go*" + freq + " ⏸ _paused_",
+		},
+		{
+			name: "escaped match",
+			hit:  SearchHit{Reminder: Reminder{Text: "c++ or go", Frequency: time.Hour, IsActive: true}, TextHeadline: mark("c++") + " or " + mark("go")},
+			want: "*c\\+\\+* or *go*" + freq,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.hit.StringMdV2("en"); got != tc.want {
+				t.Errorf("StringMdV2() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package reminder
+
+import (
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+)
+
+const noTagValue = "no_tag"
+
+// TagCount is the number of user's reminders under a tag, empty tag counts untagged reminders
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// Button is a keyboard item picking the tag as user input, labeled in the language
+func (t TagCount) Button(lang string) domain.Item {
+	if t.Tag == "" {
+		return domain.Item{Key: fmt.Sprintf(domain.Tr(lang, "No tag (%d)"), t.Count), Val: noTagValue}
+	}
+	return domain.Item{Key: fmt.Sprintf("%s (%d)", t.Tag, t.Count), Val: t.Tag}
+}
+
+// TagsKeyboard lays out tag buttons a few per row, extra items go to the last row
+func TagsKeyboard(lang string, tags []TagCount, perRow int, extra ...domain.Item) domain.Keyboard {
+	kb := make(domain.Keyboard, 0, len(tags)/perRow+2)
+
+	row := make([]domain.Item, 0, perRow)
+	for _, tag := range tags {
+		row = append(row, tag.Button(lang))
+		if len(row) == perRow {
+			kb, row = append(kb, row), make([]domain.Item, 0, perRow)
+		}
+	}
+	if len(row) > 0 {
+		kb = append(kb, row)
+	}
+
+	if len(extra) > 0 {
+		kb = append(kb, extra)
+	}
+
+	return kb
+}
+
+// TagMdV2 is the escaped tag name, untagged reminders are called `no tag` in the language
+func (t TagCount) TagMdV2(lang string) string {
+	if t.Tag == "" {
+		return domain.Tr(lang, domain.ReplyNoTag)
+	}
+	return (&Reminder{Tag: t.Tag}).TagMdV2()
+}
+
+// TotalCount sums reminders under all tags
+func TotalCount(tags []TagCount) (total int) {
+	for _, tag := range tags {
+		total += tag.Count
+	}
+	return total
+}
@@ -0,0 +1,42 @@
+package reminder
+
+import (
+	"testing"
+
+	"github.com/vedomirr/remindista/internal/domain"
+)
+
+func TestTagsKeyboard(t *testing.T) {
+	tags := []TagCount{{"#go", 3}, {"#sql", 2}, {"", 1}}
+	cancel := domain.Item{Key: "Cancel", Val: "cancel"}
+
+	kb := TagsKeyboard("en", tags, 2, cancel)
+
+	want := domain.Keyboard{
+		{{Key: "#go (3)", Val: "#go"}, {Key: "#sql (2)", Val: "#sql"}},
+		{{Key: "No tag (1)", Val: "no_tag"}},
+		{cancel},
+	}
+
+	if len(kb) != len(want) {
+		t.Fatalf("TagsKeyboard() = %v, want %v", kb, want)
+	}
+	for i := range want {
+		if len(kb[i]) != len(want[i]) {
+			t.Fatalf("row %d = %v, want %v", i, kb[i], want[i])
+		}
+		for j := range want[i] {
+			if kb[i][j] != want[i][j] {
+				t.Errorf("button %d.%d = %v, want %v", i, j, kb[i][j], want[i][j])
+			}
+		}
+	}
+
+	if got := tags[2].Button("ru").Key; got != "Без тега (1)" {
+		t.Errorf("Button() in ru = %q, want %q", got, "Без тега (1)")
+	}
+
+	if total := TotalCount(tags); total != 6 {
+		t.Errorf("TotalCount() = %d, want 6", total)
+	}
+}
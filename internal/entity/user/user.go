@@ -1,12 +1,18 @@
 package user
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"time"
 
 	_ "time/tzdata"
+
+	"github.com/vedomirr/remindista/internal/domain"
 )
 
 const (
@@ -23,6 +29,11 @@ type User struct {
 	Location    *time.Location
 	WindowFloor time.Time
 	WindowCeil  time.Time
+
+	CalendarToken string
+
+	// Language replies are translated into, empty follows user's telegram settings
+	Language string
 }
 
 type UserOption func(*User)
@@ -109,6 +120,35 @@ func (u *User) SetWindowCeil(time string) error {
 	return nil
 }
 
+// SetLanguage picks one of domain.Languages, `auto` follows telegram settings
+func (u *User) SetLanguage(lang string) error {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "auto" {
+		u.Language = ""
+		return nil
+	}
+
+	if !slices.Contains(domain.Languages, lang) {
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+
+	u.Language = lang
+
+	return nil
+}
+
+// RotateCalendarToken replaces the secret part of the user's calendar feed url
+func (u *User) RotateCalendarToken() error {
+	token := make([]byte, 24)
+	if _, err := rand.Read(token); err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	u.CalendarToken = hex.EncodeToString(token)
+
+	return nil
+}
+
 func (u *User) Time() time.Time {
 	if u.Location == nil {
 		u.loadDefaultLocation()
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *PostgresDB) SaveConversation(ctx context.Context, conv domain.Conversation) error {
+	query := `INSERT INTO data.conversations (chat_id, telegram_id, flow, step, history, state, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW())
+ON CONFLICT (chat_id) DO UPDATE
+SET telegram_id = EXCLUDED.telegram_id, flow = EXCLUDED.flow, step = EXCLUDED.step,
+	history = EXCLUDED.history, state = EXCLUDED.state, updated_at = EXCLUDED.updated_at;`
+
+	if _, err := db.conn.Exec(ctx, query,
+		conv.ChatId,
+		conv.TelegramId,
+		conv.Flow,
+		conv.Step,
+		conv.History,
+		string(conv.State),
+	); err != nil {
+		return fmt.Errorf("failed to execute upsert conversation query: %w", err)
+	}
+
+	return nil
+}
+
+func (db *PostgresDB) GetConversations(ctx context.Context) (convs []domain.Conversation, err error) {
+	query := `SELECT chat_id, telegram_id, flow, step, history, state::TEXT, updated_at
+FROM data.conversations
+ORDER BY updated_at;`
+
+	rows, err := db.conn.Query(ctx, query)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return convs, nil
+	} else if err != nil {
+		return convs, fmt.Errorf("failed to execute select conversations query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var conv domain.Conversation
+		var state string
+
+		if err := rows.Scan(
+			&conv.ChatId,
+			&conv.TelegramId,
+			&conv.Flow,
+			&conv.Step,
+			&conv.History,
+			&state,
+			&conv.UpdatedAt,
+		); err != nil {
+			return convs, fmt.Errorf("failed to scan row when quering conversations: %w", err)
+		}
+
+		conv.State = []byte(state)
+		convs = append(convs, conv)
+	}
+
+	return convs, rows.Err()
+}
+
+func (db *PostgresDB) DeleteConversation(ctx context.Context, chatId int64) (affected int, err error) {
+	tag, err := db.conn.Exec(ctx, `DELETE FROM data.conversations WHERE chat_id = $1;`, chatId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute delete conversation query: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveDelivery remembers which reminder the sent message is, so replies to it can edit the reminder
+func (db *PostgresDB) SaveDelivery(ctx context.Context, chatId int64, messageId int, rmdId int) error {
+	query := `INSERT INTO data.deliveries (chat_id, message_id, reminder_id, sent_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (chat_id, message_id) DO UPDATE
+SET reminder_id = EXCLUDED.reminder_id, sent_at = EXCLUDED.sent_at;`
+
+	if _, err := db.conn.Exec(ctx, query, chatId, messageId, rmdId); err != nil {
+		return fmt.Errorf("failed to execute insert delivery query: %w", err)
+	}
+
+	return nil
+}
+
+// GetReminderByDelivery finds user's reminder delivered as the message, deleted reminders are not found
+func (db *PostgresDB) GetReminderByDelivery(ctx context.Context, userId int, chatId int64, messageId int) (rmd r.Reminder, err error) {
+	query := `SELECT rmd.id, rmd.user_id, rmd.number, rmd.text, rmd.tag, rmd.prompt, rmd.frequency, rmd.next_reminder,
+	COALESCE(rmd.source_key, ''), rmd.window_floor, rmd.window_ceil, rmd.is_active
+FROM data.deliveries dlv
+JOIN data.reminders rmd ON rmd.id = dlv.reminder_id
+WHERE dlv.chat_id = $1 AND dlv.message_id = $2 AND rmd.user_id = $3 AND rmd.is_deleted = FALSE AND rmd.group_chat_id IS NULL;`
+
+	if err = db.conn.QueryRow(ctx, query, chatId, messageId, userId).Scan(
+		&rmd.Id,
+		&rmd.UserId,
+		&rmd.Number,
+		&rmd.Text,
+		&rmd.Tag,
+		&rmd.Prompt,
+		&rmd.Frequency,
+		&rmd.NextReminder,
+		&rmd.SourceKey,
+		&rmd.WindowFloor,
+		&rmd.WindowCeil,
+		&rmd.IsActive,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return rmd, nil
+	} else if err != nil {
+		return rmd, fmt.Errorf("failed to execute select reminder by delivery query: %w", err)
+	}
+
+	return rmd, nil
+}
+
+// PurgeDeliveries forgets messages sent before the time, replies to them aren't recognized anymore
+func (db *PostgresDB) PurgeDeliveries(ctx context.Context, before time.Time) (affected int, err error) {
+	tag, err := db.conn.Exec(ctx, `DELETE FROM data.deliveries WHERE sent_at < $1;`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute purge deliveries query: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
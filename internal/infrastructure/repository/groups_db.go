@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	g "github.com/vedomirr/remindista/internal/entity/group"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveGroup remembers the group chat or updates its title, reminder numbers counter is kept
+func (db *PostgresDB) SaveGroup(ctx context.Context, group g.Group) error {
+	query := `INSERT INTO data.groups (chat_id, title)
+VALUES ($1, $2)
+ON CONFLICT (chat_id) DO UPDATE
+SET title = EXCLUDED.title;`
+
+	if _, err := db.conn.Exec(ctx, query, group.ChatId, group.Title); err != nil {
+		return fmt.Errorf("failed to execute insert group query: %w", err)
+	}
+
+	return nil
+}
+
+// GetGroupReminders returns reminders delivered to the group, whoever created them
+func (db *PostgresDB) GetGroupReminders(ctx context.Context, chatId int64) (rmds []r.Reminder, err error) {
+	rmds = make([]r.Reminder, 0)
+
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active, group_chat_id
+FROM data.reminders
+WHERE group_chat_id = $1 AND is_deleted = FALSE;`
+
+	rows, err := db.conn.Query(ctx, query, chatId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rmds, nil
+	} else if err != nil {
+		return rmds, fmt.Errorf("failed to execute select group reminders query: %w", err)
+	}
+
+	for rows.Next() {
+		var rmd r.Reminder
+
+		if err := rows.Scan(
+			&rmd.Id,
+			&rmd.UserId,
+			&rmd.Number,
+			&rmd.Text,
+			&rmd.Tag,
+			&rmd.Prompt,
+			&rmd.Frequency,
+			&rmd.NextReminder,
+			&rmd.SourceKey,
+			&rmd.WindowFloor,
+			&rmd.WindowCeil,
+			&rmd.IsActive,
+			&rmd.GroupChatId,
+		); err != nil {
+			return rmds, fmt.Errorf("failed to scan row when quering group reminders: %w", err)
+		}
+
+		rmds = append(rmds, rmd)
+	}
+
+	return rmds, nil
+}
+
+// GetGroupReminderByNumber finds group's reminder by its per-group number
+func (db *PostgresDB) GetGroupReminderByNumber(ctx context.Context, chatId int64, number int) (rmd r.Reminder, err error) {
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active, group_chat_id
+FROM data.reminders
+WHERE group_chat_id = $1 AND number = $2 AND is_deleted = FALSE;`
+
+	if err = db.conn.QueryRow(ctx, query, chatId, number).Scan(
+		&rmd.Id,
+		&rmd.UserId,
+		&rmd.Number,
+		&rmd.Text,
+		&rmd.Tag,
+		&rmd.Prompt,
+		&rmd.Frequency,
+		&rmd.NextReminder,
+		&rmd.SourceKey,
+		&rmd.WindowFloor,
+		&rmd.WindowCeil,
+		&rmd.IsActive,
+		&rmd.GroupChatId,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return rmd, nil
+	} else if err != nil {
+		return rmd, fmt.Errorf("failed to execute select group reminder by number query: %w", err)
+	}
+
+	return rmd, nil
+}
+
+// DeleteGroupReminder moves group's reminder to trash, reminders of other chats are domain.ErrorReminderNotFound
+func (db *PostgresDB) DeleteGroupReminder(ctx context.Context, chatId int64, id int) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH rows AS (
+	UPDATE data.reminders
+	SET is_deleted = true, deleted_at = NOW()
+	WHERE id = $1 AND group_chat_id = $2 AND is_deleted = FALSE
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, id, chatId).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return 0, fmt.Errorf("failed to execute delete group reminder query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if affected == 0 {
+		return 0, domain.ErrorReminderNotFound
+	}
+
+	return affected, nil
+}
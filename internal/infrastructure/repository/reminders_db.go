@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/vedomirr/remindista/internal/domain"
 	r "github.com/vedomirr/remindista/internal/entity/reminder"
 
 	"github.com/jackc/pgx/v5"
@@ -17,8 +18,25 @@ func (db *PostgresDB) CreateReminder(ctx context.Context, rmd r.Reminder) (id in
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	query := `INSERT INTO data.reminders (user_id, text, tag, prompt, frequency, next_reminder, is_deleted)
-VALUES ($1, $2, $3, $4, $5, $6, FALSE)
+	// number is taken from user's counter, or group's one for group reminders,
+	// the row lock keeps it unique under concurrent inserts
+	counter := `UPDATE data.users
+	SET last_reminder_number = last_reminder_number + 1
+	WHERE id = $1
+	RETURNING last_reminder_number`
+	if rmd.GroupChatId != 0 {
+		counter = `UPDATE data.groups
+	SET last_reminder_number = last_reminder_number + 1
+	WHERE chat_id = $11
+	RETURNING last_reminder_number`
+	}
+
+	query := `WITH counter AS (
+	` + counter + `
+)
+INSERT INTO data.reminders (user_id, group_chat_id, number, text, tag, prompt, frequency, next_reminder, source_key, window_floor, window_ceil, is_active, is_deleted)
+SELECT $1, NULLIF($11::BIGINT, 0), counter.last_reminder_number, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, FALSE
+FROM counter
 RETURNING id;`
 
 	if err = db.conn.QueryRow(ctx, query,
@@ -28,6 +46,11 @@ RETURNING id;`
 		rmd.Prompt,
 		rmd.Frequency,
 		rmd.NextReminder,
+		rmd.SourceKey,
+		rmd.WindowFloor,
+		rmd.WindowCeil,
+		rmd.IsActive,
+		rmd.GroupChatId,
 	).Scan(&id); err != nil {
 		if errRollback := tx.Rollback(ctx); errRollback != nil {
 			err = fmt.Errorf("failed to rollback: %w", err)
@@ -42,23 +65,144 @@ RETURNING id;`
 	return id, nil
 }
 
-func (db *PostgresDB) GetReminder(ctx context.Context, id int) (rmd r.Reminder, err error) {
-	query := `SELECT id, user_id, text, tag, prompt, frequency, next_reminder
+// GetReminder finds user's reminder, someone else's one is domain.ErrorForbidden
+func (db *PostgresDB) GetReminder(ctx context.Context, userId int, id int) (rmd r.Reminder, err error) {
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active
+FROM data.reminders
+WHERE id = $1 AND user_id = $2 AND is_deleted = FALSE AND group_chat_id IS NULL;`
+
+	if err = db.conn.QueryRow(ctx, query, id, userId).Scan(
+		&rmd.Id,
+		&rmd.UserId,
+		&rmd.Number,
+		&rmd.Text,
+		&rmd.Tag,
+		&rmd.Prompt,
+		&rmd.Frequency,
+		&rmd.NextReminder,
+		&rmd.SourceKey,
+		&rmd.WindowFloor,
+		&rmd.WindowCeil,
+		&rmd.IsActive,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return rmd, db.reminderMissing(ctx, userId, id)
+	} else if err != nil {
+		return rmd, fmt.Errorf("failed to execute select reminder query: %w", err)
+	}
+
+	return rmd, nil
+}
+
+// GetSharedReminder finds reminder of any user, callers have to verify the share link signature first
+func (db *PostgresDB) GetSharedReminder(ctx context.Context, id int) (rmd r.Reminder, err error) {
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active
 FROM data.reminders
 WHERE id = $1 AND is_deleted = FALSE;`
 
 	if err = db.conn.QueryRow(ctx, query, id).Scan(
 		&rmd.Id,
 		&rmd.UserId,
+		&rmd.Number,
 		&rmd.Text,
 		&rmd.Tag,
 		&rmd.Prompt,
 		&rmd.Frequency,
 		&rmd.NextReminder,
+		&rmd.SourceKey,
+		&rmd.WindowFloor,
+		&rmd.WindowCeil,
+		&rmd.IsActive,
 	); errors.Is(err, pgx.ErrNoRows) {
 		return rmd, nil
 	} else if err != nil {
-		return rmd, fmt.Errorf("failed to execute select reminder query: %w", err)
+		return rmd, fmt.Errorf("failed to execute select shared reminder query: %w", err)
+	}
+
+	return rmd, nil
+}
+
+// reminderMissing tells why user's reminder isn't there: it's someone else's or doesn't exist in the given state
+func (db *PostgresDB) reminderMissing(ctx context.Context, userId int, id int) error {
+	var owner int
+
+	err := db.conn.QueryRow(ctx, `SELECT user_id FROM data.reminders WHERE id = $1;`, id).Scan(&owner)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return domain.ErrorReminderNotFound
+	case err != nil:
+		return fmt.Errorf("failed to execute select reminder owner query: %w", err)
+	case owner != userId:
+		return domain.ErrorForbidden
+	default:
+		return domain.ErrorReminderNotFound
+	}
+}
+
+// GetReminderByNumber finds user's reminder by its per-user number
+func (db *PostgresDB) GetReminderByNumber(ctx context.Context, userId int, number int) (rmd r.Reminder, err error) {
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active
+FROM data.reminders
+WHERE user_id = $1 AND number = $2 AND is_deleted = FALSE AND group_chat_id IS NULL;`
+
+	if err = db.conn.QueryRow(ctx, query, userId, number).Scan(
+		&rmd.Id,
+		&rmd.UserId,
+		&rmd.Number,
+		&rmd.Text,
+		&rmd.Tag,
+		&rmd.Prompt,
+		&rmd.Frequency,
+		&rmd.NextReminder,
+		&rmd.SourceKey,
+		&rmd.WindowFloor,
+		&rmd.WindowCeil,
+		&rmd.IsActive,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return rmd, nil
+	} else if err != nil {
+		return rmd, fmt.Errorf("failed to execute select reminder by number query: %w", err)
+	}
+
+	return rmd, nil
+}
+
+// GetReminderIdByNumber resolves user's reminder number to its id, deleted reminders in trash included
+func (db *PostgresDB) GetReminderIdByNumber(ctx context.Context, userId int, number int) (id int, err error) {
+	query := `SELECT id
+FROM data.reminders
+WHERE user_id = $1 AND number = $2 AND group_chat_id IS NULL;`
+
+	if err = db.conn.QueryRow(ctx, query, userId, number).Scan(&id); errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to execute select reminder id by number query: %w", err)
+	}
+
+	return id, nil
+}
+
+func (db *PostgresDB) GetReminderBySourceKey(ctx context.Context, userId int, sourceKey string) (rmd r.Reminder, err error) {
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active
+FROM data.reminders
+WHERE user_id = $1 AND source_key = $2 AND is_deleted = FALSE AND group_chat_id IS NULL;`
+
+	if err = db.conn.QueryRow(ctx, query, userId, sourceKey).Scan(
+		&rmd.Id,
+		&rmd.UserId,
+		&rmd.Number,
+		&rmd.Text,
+		&rmd.Tag,
+		&rmd.Prompt,
+		&rmd.Frequency,
+		&rmd.NextReminder,
+		&rmd.SourceKey,
+		&rmd.WindowFloor,
+		&rmd.WindowCeil,
+		&rmd.IsActive,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return rmd, nil
+	} else if err != nil {
+		return rmd, fmt.Errorf("failed to execute select reminder by source key query: %w", err)
 	}
 
 	return rmd, nil
@@ -67,9 +211,9 @@ WHERE id = $1 AND is_deleted = FALSE;`
 func (db *PostgresDB) GetRemindersByUserId(ctx context.Context, userId int) (rmds []r.Reminder, err error) {
 	rmds = make([]r.Reminder, 0)
 
-	query := `SELECT id, user_id, text, tag, prompt, frequency, next_reminder
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active
 FROM data.reminders
-WHERE user_id = $1 AND is_deleted = FALSE;`
+WHERE user_id = $1 AND is_deleted = FALSE AND group_chat_id IS NULL;`
 
 	rows, err := db.conn.Query(ctx, query, userId)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -84,11 +228,16 @@ WHERE user_id = $1 AND is_deleted = FALSE;`
 		if err := rows.Scan(
 			&rmd.Id,
 			&rmd.UserId,
+			&rmd.Number,
 			&rmd.Text,
 			&rmd.Tag,
 			&rmd.Prompt,
 			&rmd.Frequency,
 			&rmd.NextReminder,
+			&rmd.SourceKey,
+			&rmd.WindowFloor,
+			&rmd.WindowCeil,
+			&rmd.IsActive,
 		); err != nil {
 			return rmds, fmt.Errorf("failed to scan row when quering reminders: %w", err)
 		}
@@ -102,9 +251,11 @@ WHERE user_id = $1 AND is_deleted = FALSE;`
 func (db *PostgresDB) GetRemindersByUserIdAndTime(ctx context.Context, userId int, userTime time.Time) (rmds []r.Reminder, err error) {
 	rmds = make([]r.Reminder, 0)
 
-	query := `SELECT id, user_id, text, tag, prompt, frequency, next_reminder
+	// group reminders are due by their creator's time too
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active,
+	COALESCE(group_chat_id, 0)
 FROM data.reminders
-WHERE user_id = $1 AND next_reminder < $2 AND is_deleted = FALSE;`
+WHERE user_id = $1 AND next_reminder < $2 AND is_active = TRUE AND is_deleted = FALSE;`
 
 	rows, err := db.conn.Query(ctx, query, userId, userTime)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -119,11 +270,17 @@ WHERE user_id = $1 AND next_reminder < $2 AND is_deleted = FALSE;`
 		if err := rows.Scan(
 			&rmd.Id,
 			&rmd.UserId,
+			&rmd.Number,
 			&rmd.Text,
 			&rmd.Tag,
 			&rmd.Prompt,
 			&rmd.Frequency,
 			&rmd.NextReminder,
+			&rmd.SourceKey,
+			&rmd.WindowFloor,
+			&rmd.WindowCeil,
+			&rmd.IsActive,
+			&rmd.GroupChatId,
 		); err != nil {
 			return rmds, fmt.Errorf("failed to scan row when quering reminders: %w", err)
 		}
@@ -134,6 +291,7 @@ WHERE user_id = $1 AND next_reminder < $2 AND is_deleted = FALSE;`
 	return rmds, nil
 }
 
+// UpdateReminder changes reminder of rmd.UserId, the owner itself is never changed
 func (db *PostgresDB) UpdateReminder(ctx context.Context, rmd r.Reminder) (affected int, err error) {
 	tx, err := db.conn.Begin(ctx)
 	if err != nil {
@@ -142,8 +300,9 @@ func (db *PostgresDB) UpdateReminder(ctx context.Context, rmd r.Reminder) (affec
 
 	query := `WITH rows AS (
 	UPDATE data.reminders
-	SET user_id = $2, text = $3, tag = $4, prompt = $5, frequency = $6, next_reminder = $7
-	WHERE id = $1 AND is_deleted = FALSE
+	SET text = $3, tag = $4, prompt = $5, frequency = $6, next_reminder = $7, source_key = NULLIF($8, ''),
+		window_floor = $9, window_ceil = $10, is_active = $11
+	WHERE id = $1 AND user_id = $2 AND is_deleted = FALSE
 	RETURNING 1
 ) SELECT COUNT(*) FROM rows;`
 
@@ -155,6 +314,10 @@ func (db *PostgresDB) UpdateReminder(ctx context.Context, rmd r.Reminder) (affec
 		rmd.Prompt,
 		rmd.Frequency,
 		rmd.NextReminder,
+		rmd.SourceKey,
+		rmd.WindowFloor,
+		rmd.WindowCeil,
+		rmd.IsActive,
 	).Scan(&affected); err != nil {
 		if errRollback := tx.Rollback(ctx); errRollback != nil {
 			err = fmt.Errorf("failed to rollback: %w", err)
@@ -166,10 +329,14 @@ func (db *PostgresDB) UpdateReminder(ctx context.Context, rmd r.Reminder) (affec
 		return affected, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	if affected == 0 {
+		return 0, db.reminderMissing(ctx, rmd.UserId, rmd.Id)
+	}
+
 	return affected, nil
 }
 
-func (db *PostgresDB) DeleteReminder(ctx context.Context, id int) (affected int, err error) {
+func (db *PostgresDB) DeleteReminder(ctx context.Context, userId int, id int) (affected int, err error) {
 	tx, err := db.conn.Begin(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
@@ -177,12 +344,12 @@ func (db *PostgresDB) DeleteReminder(ctx context.Context, id int) (affected int,
 
 	query := `WITH rows AS (
 	UPDATE data.reminders
-	SET is_deleted = true
-	WHERE id = $1 AND is_deleted = FALSE
+	SET is_deleted = true, deleted_at = NOW()
+	WHERE id = $1 AND user_id = $2 AND is_deleted = FALSE AND group_chat_id IS NULL
 	RETURNING 1
 ) SELECT COUNT(*) FROM rows;`
 
-	if err = db.conn.QueryRow(ctx, query, id).Scan(&affected); err != nil {
+	if err = db.conn.QueryRow(ctx, query, id, userId).Scan(&affected); err != nil {
 		if errRollback := tx.Rollback(ctx); errRollback != nil {
 			err = fmt.Errorf("failed to rollback: %w", err)
 		}
@@ -193,6 +360,10 @@ func (db *PostgresDB) DeleteReminder(ctx context.Context, id int) (affected int,
 		return affected, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	if affected == 0 {
+		return 0, db.reminderMissing(ctx, userId, id)
+	}
+
 	return affected, nil
 }
 
@@ -204,8 +375,8 @@ func (db *PostgresDB) DeleteRemindersByTag(ctx context.Context, userId int, tag
 
 	query := `WITH rows AS (
 	UPDATE data.reminders
-	SET is_deleted = true
-	WHERE user_id = $1 AND tag = $2 AND is_deleted = FALSE
+	SET is_deleted = true, deleted_at = NOW()
+	WHERE user_id = $1 AND tag = $2 AND is_deleted = FALSE AND group_chat_id IS NULL
 	RETURNING 1
 ) SELECT COUNT(*) FROM rows;`
 
@@ -231,8 +402,8 @@ func (db *PostgresDB) DeleteRemindersByUserId(ctx context.Context, userId int) (
 
 	query := `WITH rows AS (
 	UPDATE data.reminders
-	SET is_deleted = true
-	WHERE user_id = $1 AND is_deleted = FALSE
+	SET is_deleted = true, deleted_at = NOW()
+	WHERE user_id = $1 AND is_deleted = FALSE AND group_chat_id IS NULL
 	RETURNING 1
 ) SELECT COUNT(*) FROM rows;`
 
@@ -249,3 +420,293 @@ func (db *PostgresDB) DeleteRemindersByUserId(ctx context.Context, userId int) (
 
 	return affected, nil
 }
+
+// GetDeletedRemindersByUserId returns user's deleted reminders starting from the most recently deleted
+func (db *PostgresDB) GetDeletedRemindersByUserId(ctx context.Context, userId int, limit int) (rmds []r.Reminder, err error) {
+	rmds = make([]r.Reminder, 0)
+
+	query := `SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active, deleted_at
+FROM data.reminders
+WHERE user_id = $1 AND is_deleted = TRUE AND group_chat_id IS NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT $2;`
+
+	rows, err := db.conn.Query(ctx, query, userId, limit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return rmds, nil
+	} else if err != nil {
+		return rmds, fmt.Errorf("failed to execute select deleted reminders query: %w", err)
+	}
+
+	for rows.Next() {
+		var rmd r.Reminder
+
+		if err := rows.Scan(
+			&rmd.Id,
+			&rmd.UserId,
+			&rmd.Number,
+			&rmd.Text,
+			&rmd.Tag,
+			&rmd.Prompt,
+			&rmd.Frequency,
+			&rmd.NextReminder,
+			&rmd.SourceKey,
+			&rmd.WindowFloor,
+			&rmd.WindowCeil,
+			&rmd.IsActive,
+			&rmd.DeletedAt,
+		); err != nil {
+			return rmds, fmt.Errorf("failed to scan row when quering deleted reminders: %w", err)
+		}
+
+		rmds = append(rmds, rmd)
+	}
+
+	return rmds, nil
+}
+
+// RestoreReminders brings back the reminder along with the ones deleted by the same command.
+// Cards imported again since then keep their source key, restored ones are detached from the deck
+func (db *PostgresDB) RestoreReminders(ctx context.Context, userId int, id int) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH deleted AS (
+	SELECT user_id, deleted_at
+	FROM data.reminders
+	WHERE id = $1 AND user_id = $2 AND is_deleted = TRUE AND group_chat_id IS NULL
+), rows AS (
+	UPDATE data.reminders rmd
+	SET is_deleted = false, deleted_at = NULL, source_key = CASE WHEN EXISTS (
+		SELECT 1 FROM data.reminders live
+		WHERE live.user_id = rmd.user_id AND live.source_key = rmd.source_key AND live.is_deleted = FALSE
+	) THEN NULL ELSE rmd.source_key END
+	FROM deleted
+	WHERE rmd.user_id = deleted.user_id AND rmd.deleted_at = deleted.deleted_at AND rmd.is_deleted = TRUE
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, id, userId).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return 0, fmt.Errorf("failed to execute restore reminders query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if affected == 0 {
+		return 0, db.reminderMissing(ctx, userId, id)
+	}
+
+	return affected, nil
+}
+
+// RestoreReminder brings back a single deleted reminder, detaching it from the deck if its card was imported again
+func (db *PostgresDB) RestoreReminder(ctx context.Context, userId int, id int) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH rows AS (
+	UPDATE data.reminders rmd
+	SET is_deleted = false, deleted_at = NULL, source_key = CASE WHEN EXISTS (
+		SELECT 1 FROM data.reminders live
+		WHERE live.user_id = rmd.user_id AND live.source_key = rmd.source_key AND live.is_deleted = FALSE
+	) THEN NULL ELSE rmd.source_key END
+	WHERE rmd.id = $1 AND rmd.user_id = $2 AND rmd.is_deleted = TRUE AND rmd.group_chat_id IS NULL
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, id, userId).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return 0, fmt.Errorf("failed to execute restore reminder query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if affected == 0 {
+		return 0, db.reminderMissing(ctx, userId, id)
+	}
+
+	return affected, nil
+}
+
+// PurgeReminder removes a deleted reminder for good
+func (db *PostgresDB) PurgeReminder(ctx context.Context, userId int, id int) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH rows AS (
+	DELETE FROM data.reminders
+	WHERE id = $1 AND user_id = $2 AND is_deleted = TRUE AND group_chat_id IS NULL
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, id, userId).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return 0, fmt.Errorf("failed to execute purge reminder query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if affected == 0 {
+		return 0, db.reminderMissing(ctx, userId, id)
+	}
+
+	return affected, nil
+}
+
+// PurgeDeletedReminders removes reminders deleted before the given time for good
+func (db *PostgresDB) PurgeDeletedReminders(ctx context.Context, before time.Time) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH rows AS (
+	DELETE FROM data.reminders
+	WHERE is_deleted = TRUE AND deleted_at < $1
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, before).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return 0, fmt.Errorf("failed to execute purge reminders query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return affected, nil
+}
+
+// SearchReminders ranks user's reminders against the query in english and russian, best matches first
+func (db *PostgresDB) SearchReminders(ctx context.Context, userId int, query string, limit int) (hits []r.SearchHit, err error) {
+	hits = make([]r.SearchHit, 0)
+
+	headline := fmt.Sprintf(`StartSel="%s", StopSel="%s", HighlightAll=true`, r.HighlightStart, r.HighlightStop)
+
+	sql := `WITH q AS (
+	SELECT websearch_to_tsquery('english', $2) || websearch_to_tsquery('russian', $2) AS query
+)
+SELECT id, user_id, number, text, tag, prompt, frequency, next_reminder, COALESCE(source_key, ''), window_floor, window_ceil, is_active,
+	ts_headline(CASE WHEN text ~ '[А-Яа-яЁё]' THEN 'russian' ELSE 'english' END::regconfig, text, q.query, $3),
+	ts_headline(CASE WHEN prompt ~ '[А-Яа-яЁё]' THEN 'russian' ELSE 'english' END::regconfig, COALESCE(prompt, ''), q.query, $3),
+	ts_rank_cd(search_vector, q.query) AS rank
+FROM data.reminders, q
+WHERE user_id = $1 AND is_deleted = FALSE AND group_chat_id IS NULL AND search_vector @@ q.query
+ORDER BY rank DESC, id
+LIMIT $4;`
+
+	rows, err := db.conn.Query(ctx, sql, userId, query, headline, limit)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return hits, nil
+	} else if err != nil {
+		return hits, fmt.Errorf("failed to execute search reminders query: %w", err)
+	}
+
+	for rows.Next() {
+		var hit r.SearchHit
+
+		if err := rows.Scan(
+			&hit.Id,
+			&hit.UserId,
+			&hit.Number,
+			&hit.Text,
+			&hit.Tag,
+			&hit.Prompt,
+			&hit.Frequency,
+			&hit.NextReminder,
+			&hit.SourceKey,
+			&hit.WindowFloor,
+			&hit.WindowCeil,
+			&hit.IsActive,
+			&hit.TextHeadline,
+			&hit.PromptHeadline,
+			&hit.Rank,
+		); err != nil {
+			return hits, fmt.Errorf("failed to scan row when searching reminders: %w", err)
+		}
+
+		hits = append(hits, hit)
+	}
+
+	return hits, nil
+}
+
+// GetTagCounts returns user's tags with the number of reminders under each, most used first
+func (db *PostgresDB) GetTagCounts(ctx context.Context, userId int) (tags []r.TagCount, err error) {
+	tags = make([]r.TagCount, 0)
+
+	query := `SELECT COALESCE(tag, ''), COUNT(*) AS count
+FROM data.reminders
+WHERE user_id = $1 AND is_deleted = FALSE AND group_chat_id IS NULL
+GROUP BY COALESCE(tag, '')
+ORDER BY count DESC, COALESCE(tag, '');`
+
+	rows, err := db.conn.Query(ctx, query, userId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return tags, nil
+	} else if err != nil {
+		return tags, fmt.Errorf("failed to execute select tag counts query: %w", err)
+	}
+
+	for rows.Next() {
+		var tag r.TagCount
+
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return tags, fmt.Errorf("failed to scan row when quering tag counts: %w", err)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// RenameTag moves user's reminders from one tag to another, renaming to an existing tag merges them
+func (db *PostgresDB) RenameTag(ctx context.Context, userId int, from, to string) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH rows AS (
+	UPDATE data.reminders
+	SET tag = $3
+	WHERE user_id = $1 AND COALESCE(tag, '') = $2 AND is_deleted = FALSE AND group_chat_id IS NULL
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, userId, from, to).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return 0, fmt.Errorf("failed to execute rename tag query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// testDB connects to the database from TEST_DATABASE_URL migrated with db/migrations, tests are skipped without it
+func testDB(t *testing.T) *PostgresDB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return NewPostgresDB(pool)
+}
+
+func testUser(t *testing.T, db *PostgresDB) u.User {
+	t.Helper()
+	ctx := context.Background()
+
+	tgId := time.Now().UnixNano()
+	user := u.NewUser(u.WithTelegramId(tgId), u.WithChatId(tgId))
+
+	id, err := db.CreateUser(ctx, user)
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	user.Id = id
+
+	t.Cleanup(func() {
+		db.conn.Exec(ctx, `DELETE FROM data.reminders WHERE user_id = $1;`, id)
+		db.conn.Exec(ctx, `DELETE FROM data.users WHERE id = $1;`, id)
+	})
+
+	return user
+}
+
+func TestPostgresDB_ReminderOwnership(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	owner, stranger := testUser(t, db), testUser(t, db)
+
+	rmd := r.NewReminder(r.WithUserId(owner.Id))
+	rmd.Text, rmd.Frequency, rmd.NextReminder = "Goroutines", time.Hour, time.Now().Add(time.Hour)
+
+	id, err := db.CreateReminder(ctx, rmd)
+	if err != nil {
+		t.Fatalf("CreateReminder() error = %v", err)
+	}
+	rmd.Id = id
+
+	if _, err := db.GetReminder(ctx, stranger.Id, id); !errors.Is(err, domain.ErrorForbidden) {
+		t.Errorf("GetReminder() by stranger error = %v, want forbidden", err)
+	}
+
+	forged := rmd
+	forged.UserId, forged.Text = stranger.Id, "Hijacked"
+	if _, err := db.UpdateReminder(ctx, forged); !errors.Is(err, domain.ErrorForbidden) {
+		t.Errorf("UpdateReminder() by stranger error = %v, want forbidden", err)
+	}
+
+	if _, err := db.DeleteReminder(ctx, stranger.Id, id); !errors.Is(err, domain.ErrorForbidden) {
+		t.Errorf("DeleteReminder() by stranger error = %v, want forbidden", err)
+	}
+
+	if _, err := db.DeleteReminder(ctx, owner.Id, id); err != nil {
+		t.Fatalf("DeleteReminder() by owner error = %v", err)
+	}
+
+	if _, err := db.RestoreReminder(ctx, stranger.Id, id); !errors.Is(err, domain.ErrorForbidden) {
+		t.Errorf("RestoreReminder() by stranger error = %v, want forbidden", err)
+	}
+	if _, err := db.RestoreReminders(ctx, stranger.Id, id); !errors.Is(err, domain.ErrorForbidden) {
+		t.Errorf("RestoreReminders() by stranger error = %v, want forbidden", err)
+	}
+	if _, err := db.PurgeReminder(ctx, stranger.Id, id); !errors.Is(err, domain.ErrorForbidden) {
+		t.Errorf("PurgeReminder() by stranger error = %v, want forbidden", err)
+	}
+
+	if _, err := db.RestoreReminder(ctx, owner.Id, id); err != nil {
+		t.Fatalf("RestoreReminder() by owner error = %v", err)
+	}
+
+	got, err := db.GetReminder(ctx, owner.Id, id)
+	if err != nil {
+		t.Fatalf("GetReminder() by owner error = %v", err)
+	}
+	if got.Text != "Goroutines" {
+		t.Errorf("reminder text = %q, stranger's update went through", got.Text)
+	}
+
+	if _, err := db.GetReminder(ctx, owner.Id, id+1_000_000); !errors.Is(err, domain.ErrorReminderNotFound) {
+		t.Errorf("GetReminder() of missing reminder error = %v, want not found", err)
+	}
+}
+
+func TestPostgresDB_RestoreReimportedCard(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	owner := testUser(t, db)
+
+	create := func(text string) int {
+		t.Helper()
+
+		rmd := r.NewReminder(r.WithUserId(owner.Id), r.WithSourceKey("deck:1"))
+		rmd.Text, rmd.Frequency, rmd.NextReminder = text, time.Hour, time.Now().Add(time.Hour)
+
+		id, err := db.CreateReminder(ctx, rmd)
+		if err != nil {
+			t.Fatalf("CreateReminder() error = %v", err)
+		}
+		return id
+	}
+
+	restores := []struct {
+		name    string
+		restore func(ctx context.Context, userId int, id int) (int, error)
+	}{
+		{"RestoreReminder", db.RestoreReminder},
+		{"RestoreReminders", db.RestoreReminders},
+	}
+
+	for _, tt := range restores {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := create("Channels")
+			if _, err := db.DeleteReminder(ctx, owner.Id, deleted); err != nil {
+				t.Fatalf("DeleteReminder() error = %v", err)
+			}
+
+			reimported := create("Channels")
+			t.Cleanup(func() {
+				db.conn.Exec(ctx, `DELETE FROM data.reminders WHERE id = ANY($1);`, []int{deleted, reimported})
+			})
+
+			if _, err := tt.restore(ctx, owner.Id, deleted); err != nil {
+				t.Fatalf("%s() of re-imported card error = %v", tt.name, err)
+			}
+
+			got, err := db.GetReminder(ctx, owner.Id, deleted)
+			if err != nil {
+				t.Fatalf("GetReminder() error = %v", err)
+			}
+			if got.SourceKey != "" {
+				t.Errorf("restored source key = %q, want it detached", got.SourceKey)
+			}
+
+			got, err = db.GetReminderBySourceKey(ctx, owner.Id, "deck:1")
+			if err != nil {
+				t.Fatalf("GetReminderBySourceKey() error = %v", err)
+			}
+			if got.Id != reimported {
+				t.Errorf("source key belongs to %d, want re-imported %d", got.Id, reimported)
+			}
+		})
+	}
+}
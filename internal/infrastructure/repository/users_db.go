@@ -17,8 +17,8 @@ func (db *PostgresDB) CreateUser(ctx context.Context, user u.User) (id int, err
 		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
-	query := `INSERT INTO data.users (telegram_id, chat_id, is_running, location, window_floor, window_ceil, is_deleted)
-VALUES ($1, $2, $3, $4, $5, $6, FALSE)
+	query := `INSERT INTO data.users (telegram_id, chat_id, is_running, location, window_floor, window_ceil, language, is_deleted)
+VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), FALSE)
 RETURNING id;`
 
 	if err = db.conn.QueryRow(ctx, query,
@@ -28,6 +28,7 @@ RETURNING id;`
 		user.Location.String(),
 		user.WindowFloor,
 		user.WindowCeil,
+		user.Language,
 	).Scan(&id); err != nil {
 		if errRollback := tx.Rollback(ctx); errRollback != nil {
 			err = fmt.Errorf("failed to rollback: %w", err)
@@ -43,7 +44,7 @@ RETURNING id;`
 }
 
 func (db *PostgresDB) GetUser(ctx context.Context, id int) (user u.User, err error) {
-	query := `SELECT id, telegram_id, chat_id, is_running, location, window_floor, window_ceil
+	query := `SELECT id, telegram_id, chat_id, is_running, location, window_floor, window_ceil, COALESCE(calendar_token, ''), COALESCE(language, '')
 FROM data.users
 WHERE id = $1 AND is_deleted = FALSE;`
 
@@ -56,6 +57,8 @@ WHERE id = $1 AND is_deleted = FALSE;`
 		&locationName,
 		&user.WindowFloor,
 		&user.WindowCeil,
+		&user.CalendarToken,
+		&user.Language,
 	); errors.Is(err, pgx.ErrNoRows) {
 		return user, nil
 	} else if err != nil {
@@ -70,7 +73,7 @@ WHERE id = $1 AND is_deleted = FALSE;`
 }
 
 func (db *PostgresDB) GetUserByTelegramId(ctx context.Context, telegramId int64) (user u.User, err error) {
-	query := `SELECT id, telegram_id, chat_id, is_running, location, window_floor, window_ceil
+	query := `SELECT id, telegram_id, chat_id, is_running, location, window_floor, window_ceil, COALESCE(calendar_token, ''), COALESCE(language, '')
 FROM data.users
 WHERE telegram_id = $1 AND is_deleted = FALSE;`
 
@@ -83,6 +86,8 @@ WHERE telegram_id = $1 AND is_deleted = FALSE;`
 		&locationName,
 		&user.WindowFloor,
 		&user.WindowCeil,
+		&user.CalendarToken,
+		&user.Language,
 	); errors.Is(err, pgx.ErrNoRows) {
 		return user, nil
 	} else if err != nil {
@@ -96,8 +101,37 @@ WHERE telegram_id = $1 AND is_deleted = FALSE;`
 	return user, nil
 }
 
+func (db *PostgresDB) GetUserByCalendarToken(ctx context.Context, token string) (user u.User, err error) {
+	query := `SELECT id, telegram_id, chat_id, is_running, location, window_floor, window_ceil, COALESCE(calendar_token, ''), COALESCE(language, '')
+FROM data.users
+WHERE calendar_token = $1 AND is_deleted = FALSE;`
+
+	var locationName string
+	if err = db.conn.QueryRow(ctx, query, token).Scan(
+		&user.Id,
+		&user.TelegramId,
+		&user.ChatId,
+		&user.IsRunning,
+		&locationName,
+		&user.WindowFloor,
+		&user.WindowCeil,
+		&user.CalendarToken,
+		&user.Language,
+	); errors.Is(err, pgx.ErrNoRows) {
+		return user, nil
+	} else if err != nil {
+		return user, fmt.Errorf("failed to execute select user by calendar token query: %w", err)
+	}
+
+	if err := user.SetLocation(locationName); err != nil {
+		db.log.Error("failed to load user location", zap.Int("user id", user.Id), zap.Error(err))
+	}
+
+	return user, nil
+}
+
 func (db PostgresDB) GetAllUsers(ctx context.Context, limit int, offset int) (users []u.User, err error) {
-	query := `SELECT id, telegram_id, chat_id, location, window_floor, window_ceil
+	query := `SELECT id, telegram_id, chat_id, location, window_floor, window_ceil, COALESCE(language, '')
 FROM data.users
 WHERE is_deleted = FALSE
 LIMIT $1
@@ -121,6 +155,7 @@ OFFSET $2;`
 			&locationName,
 			&user.WindowFloor,
 			&user.WindowCeil,
+			&user.Language,
 		); err != nil {
 			return users, fmt.Errorf("failed to scan row when quering users: %w", err)
 		}
@@ -143,7 +178,7 @@ func (db *PostgresDB) UpdateUser(ctx context.Context, user u.User) (affected int
 
 	query := `WITH rows AS (
 	UPDATE data.users
-	SET telegram_id = $2, chat_id = $3, is_running = $4, location = $5, window_floor = $6, window_ceil = $7
+	SET telegram_id = $2, chat_id = $3, is_running = $4, location = $5, window_floor = $6, window_ceil = $7, language = NULLIF($8, '')
 	WHERE id = $1 AND is_deleted = FALSE
 	RETURNING 1
 ) SELECT COUNT(*) FROM rows;`
@@ -156,6 +191,7 @@ func (db *PostgresDB) UpdateUser(ctx context.Context, user u.User) (affected int
 		user.Location.String(),
 		user.WindowFloor,
 		user.WindowCeil,
+		user.Language,
 	).Scan(&affected); err != nil {
 		if errRollback := tx.Rollback(ctx); errRollback != nil {
 			err = fmt.Errorf("failed to rollback: %w", err)
@@ -170,6 +206,33 @@ func (db *PostgresDB) UpdateUser(ctx context.Context, user u.User) (affected int
 	return affected, nil
 }
 
+func (db *PostgresDB) UpdateUserCalendarToken(ctx context.Context, id int, token string) (affected int, err error) {
+	tx, err := db.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	query := `WITH rows AS (
+	UPDATE data.users
+	SET calendar_token = $2
+	WHERE id = $1 AND is_deleted = FALSE
+	RETURNING 1
+) SELECT COUNT(*) FROM rows;`
+
+	if err = db.conn.QueryRow(ctx, query, id, token).Scan(&affected); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			err = fmt.Errorf("failed to rollback: %w", err)
+		}
+		return affected, fmt.Errorf("failed to execute update user calendar token query: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return affected, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return affected, nil
+}
+
 func (db *PostgresDB) DeleteUser(ctx context.Context, id int, telegramId int64) (affected int, err error) {
 	tx, err := db.conn.Begin(ctx)
 	if err != nil {
@@ -2,20 +2,30 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/vedomirr/remindista/internal/domain"
 	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
 
 	"go.uber.org/zap"
 )
 
 type ChatAddReminder struct {
 	*Chat
+	line string
 }
 
-func NewChatAddReminder(chat *Chat) *ChatAddReminder {
-	c := &ChatAddReminder{chat}
+type reminderState struct {
+	User u.User     `json:"-"`
+	Rmd  r.Reminder `json:"reminder"`
+}
+
+// NewChatAddReminder creates reminder right away from one-line description, otherwise asks for the missing parts
+func NewChatAddReminder(chat *Chat, line string) *ChatAddReminder {
+	c := &ChatAddReminder{chat, strings.TrimSpace(line)}
 
 	go c.chat()
 
@@ -23,7 +33,6 @@ func NewChatAddReminder(chat *Chat) *ChatAddReminder {
 }
 
 func (c *ChatAddReminder) chat() {
-	defer close(c.inCh)
 	defer c.deleteChat()
 
 	// check if user exists
@@ -33,68 +42,116 @@ func (c *ChatAddReminder) chat() {
 		return
 	}
 
-	// initialize reminder object
-	rmd := r.NewReminder(r.WithUserId(user.Id))
+	f := c.flow()
+	state := &reminderState{User: user, Rmd: r.NewReminder(r.WithUserId(user.Id))}
+
+	if c.line != "" {
+		rmd := state.Rmd
+		switch err := rmd.ParseQuickAdd(c.line); {
+		case err != nil:
+			// nothing is asked after the error, user fixes the line or adds the reminder step by step
+			c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorParsingQuickAdd), err).Error(), nil)
+			return
 
-	stage := "text"
-	c.SendMessage(domain.ReplySetReminderText, domain.KbCancel)
+		case rmd.Frequency == 0:
+			state.Rmd = rmd
+			f.start = "frequency"
 
-	for msg := range c.inCh {
-		if c.isCancel(msg) { // cancel the process
-			c.SendMessage(domain.ReplyCancel, nil)
+		default:
+			state.Rmd = rmd
+			state.Rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+			f.done(state)
 			return
 		}
+	}
 
-		switch stage {
-		case "text":
-			rmd.Text = msg
-
-			c.SendMessage(domain.ReplySetReminderTag, domain.KbSkip)
-			stage = "tag"
+	converse(c.Chat, f, state)
+}
 
-		case "tag":
-			if !c.skipped(msg) { // do only if this stage was not skipped
-				if err := rmd.SetTag(msg); err != nil {
-					c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingTag, err).Error(), domain.KbSkip)
-					break
-				}
+func (c *ChatAddReminder) flow() flow[reminderState] {
+	return flow[reminderState]{
+		name: flowAddReminder,
+		steps: []step[reminderState]{
+			{
+				name: "text",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					// came back to this step, text can be kept
+					if s.Rmd.Text != "" {
+						return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderText), s.Rmd.TextMdV2()), domain.KbSkip
+					}
+					return domain.ReplySetReminderText, domain.KbCancel
+				},
+				apply: func(s *reminderState, msg string) error {
+					s.Rmd.Text = msg
+					return nil
+				},
+				skip: func(s *reminderState) error {
+					if s.Rmd.Text == "" {
+						return errors.New(domain.ReplyCannotSkip)
+					}
+					return nil
+				},
+			},
+			{
+				name: "tag",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					if s.Rmd.Tag != "" {
+						return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderTag), s.Rmd.TagMdV2()), domain.KbSkip
+					}
+					return domain.ReplySetReminderTag, domain.KbSkip
+				},
+				apply: c.applyTag,
+				skip:  skipStep[reminderState],
+			},
+			{
+				name: "prompt",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					if s.Rmd.Prompt != "" {
+						return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderPrompt), s.Rmd.PromptMdV2()), domain.KbSkip
+					}
+					return domain.ReplySetReminderPrompt, domain.KbSkip
+				},
+				apply: c.applyPrompt,
+				skip:  skipStep[reminderState],
+			},
+			{
+				name: "frequency",
+				prompt: func(*reminderState) (string, domain.Keyboard) {
+					return domain.ReplySetReminderFrequency, domain.KbCancel
+				},
+				apply: c.applyFrequency,
+			},
+		},
+		done: func(s *reminderState) {
+			if _, err := c.db.CreateReminder(context.Background(), s.Rmd); err != nil {
+				c.log.Error("failed to create reminder", zap.Error(err))
+				c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorCreatingReminder), err).Error(), nil)
+				return
 			}
 
-			c.SendMessage(domain.ReplySetReminderPrompt, domain.KbSkip)
-			stage = "prompt"
-
-		case "prompt":
-			if !c.skipped(msg) { // do only if adding prompt was not skipped
-				rmd.Prompt = msg
-			}
+			c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyReminderSet), s.Rmd.NextReminderString(c.lang)), nil)
+		},
+	}
+}
 
-			c.SendMessage(domain.ReplySetReminderFrequency, domain.KbCancel)
-			stage = "frequency"
+func (c *Chat) applyTag(s *reminderState, msg string) error {
+	if err := s.Rmd.SetTag(msg); err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorParsingTag), err)
+	}
+	return nil
+}
 
-		case "frequency":
-			if c.skipped(msg) {
-				c.SendMessage(domain.ReplyCannotSkip, nil)
-				c.SendMessage(domain.ReplySetReminderFrequency, domain.KbCancel)
-				break
-			}
-			if err := rmd.SetFrequency(msg); err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingFrequency, err).Error(), domain.KbCancel)
-				break
-			}
-			rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+func (c *Chat) applyPrompt(s *reminderState, msg string) error {
+	s.Rmd.Prompt = msg
+	return nil
+}
 
-			if _, err := c.db.CreateReminder(context.Background(), rmd); err != nil {
-				c.log.Error("failed to create reminder", zap.Error(err))
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorCreatingReminder, err).Error(), nil)
-				return
-			}
+func (c *Chat) applyFrequency(s *reminderState, msg string) error {
+	if err := s.Rmd.SetFrequency(msg); err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorParsingFrequency), err)
+	}
 
-			c.SendMessage(fmt.Sprintf(domain.ReplyReminderSet, rmd.NextReminderString()), nil)
-			return
+	s.Rmd.UpdateNextReminder(s.User.Time(), s.User.FloorDuration(), s.User.CeilDuration())
 
-		default:
-			c.log.Error("unknown stage", zap.String("stage", stage))
-			return
-		}
-	}
+	return nil
 }
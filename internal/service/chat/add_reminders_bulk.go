@@ -0,0 +1,192 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+
+	"go.uber.org/zap"
+)
+
+const bulkSeparator = "---"
+
+type ChatAddRemindersBulk struct {
+	*Chat
+	text string
+}
+
+// NewChatAddRemindersBulk goes straight to the frequency if reminders are given, otherwise asks for them
+func NewChatAddRemindersBulk(chat *Chat, text string) *ChatAddRemindersBulk {
+	c := &ChatAddRemindersBulk{chat, strings.TrimSpace(text)}
+
+	go c.chat()
+
+	return c
+}
+
+func (c *ChatAddRemindersBulk) chat() {
+	defer c.deleteChat()
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	f := c.flow()
+	state := &bulkState{User: user}
+
+	if c.text != "" {
+		if err := f.steps[0].apply(state, c.text); err != nil {
+			c.SendMessage(err.Error(), nil)
+		} else {
+			f.start = "frequency"
+		}
+	}
+
+	converse(c.Chat, f, state)
+}
+
+type bulkState struct {
+	User    u.User      `json:"-"`
+	Text    string      `json:"text"`
+	Entries []bulkEntry `json:"-"`
+}
+
+func (c *ChatAddRemindersBulk) flow() flow[bulkState] {
+	return flow[bulkState]{
+		name: flowAddRemindersBulk,
+		steps: []step[bulkState]{
+			{
+				name: "reminders",
+				prompt: func(*bulkState) (string, domain.Keyboard) {
+					return domain.ReplySendBulkReminders, domain.KbCancel
+				},
+				apply: func(s *bulkState, msg string) error {
+					if s.Text, s.Entries = msg, parseBulk(msg, s.User.Id); len(s.Entries) == 0 {
+						return errors.New(domain.ReplyNoBulkEntries)
+					}
+
+					if countValid(s.Entries) == 0 {
+						var report strings.Builder
+						for i, entry := range s.Entries {
+							report.WriteString(fmt.Sprintf(c.tr(domain.ReplyBulkEntryFailed), i+1, entry.err.Error()))
+						}
+						return errors.New(c.tr(domain.ReplyNoBulkEntries) + report.String())
+					}
+
+					return nil
+				},
+			},
+			{
+				name: "frequency",
+				prompt: func(s *bulkState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplySetBulkFrequency), countValid(s.Entries)), domain.KbCancel
+				},
+				apply: func(s *bulkState, msg string) error {
+					freq := r.NewReminder()
+					if err := freq.SetFrequency(msg); err != nil {
+						return fmt.Errorf(c.tr(domain.ReplyErrorParsingFrequency), err)
+					}
+
+					for i := range s.Entries {
+						s.Entries[i].rmd.Frequency = freq.Frequency
+						s.Entries[i].rmd.UpdateNextReminder(s.User.Time(), s.User.FloorDuration(), s.User.CeilDuration())
+					}
+
+					return nil
+				},
+			},
+		},
+		done: c.create,
+		restore: func(s *bulkState) {
+			s.Entries = parseBulk(s.Text, s.User.Id)
+		},
+	}
+}
+
+func (c *ChatAddRemindersBulk) create(s *bulkState) {
+	nCreated := 0
+
+	var report strings.Builder
+	for i, entry := range s.Entries {
+		if entry.err != nil {
+			report.WriteString(fmt.Sprintf(c.tr(domain.ReplyBulkEntryFailed), i+1, entry.err.Error()))
+			continue
+		}
+
+		if _, err := c.db.CreateReminder(context.Background(), entry.rmd); err != nil {
+			c.log.Error("failed to create reminder", zap.Error(err))
+			report.WriteString(fmt.Sprintf(c.tr(domain.ReplyBulkEntryFailed), i+1, c.tr(domain.ReplyFailedToSave)))
+			continue
+		}
+
+		nCreated++
+		report.WriteString(fmt.Sprintf(c.tr(domain.ReplyBulkEntryCreated), entry.rmd.TextMdV2()))
+	}
+
+	c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyBulkCreated), nCreated, len(s.Entries))+report.String(), nil)
+}
+
+type bulkEntry struct {
+	rmd r.Reminder
+	err error
+}
+
+// parseBulk splits a message into reminders: by "---" lines if there are any, otherwise line by line
+func parseBulk(msg string, userId int) []bulkEntry {
+	lines := strings.Split(strings.ReplaceAll(msg, "\r\n", "\n"), "\n")
+
+	var blocks []string
+	if isBlockMode(lines) {
+		var block []string
+		for _, line := range lines {
+			if strings.TrimSpace(line) == bulkSeparator {
+				blocks = append(blocks, strings.Join(block, "\n"))
+				block = nil
+				continue
+			}
+			block = append(block, line)
+		}
+		blocks = append(blocks, strings.Join(block, "\n"))
+	} else {
+		blocks = lines
+	}
+
+	entries := make([]bulkEntry, 0, len(blocks))
+	for _, block := range blocks {
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		rmd := r.NewReminder(r.WithUserId(userId))
+		err := rmd.ParseLine(block)
+
+		entries = append(entries, bulkEntry{rmd: rmd, err: err})
+	}
+
+	return entries
+}
+
+func countValid(entries []bulkEntry) (n int) {
+	for _, entry := range entries {
+		if entry.err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+func isBlockMode(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == bulkSeparator {
+			return true
+		}
+	}
+	return false
+}
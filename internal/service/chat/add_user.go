@@ -2,6 +2,7 @@ package chat
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	_ "time/tzdata"
@@ -16,6 +17,52 @@ type ChatAddUser struct {
 	*Chat
 }
 
+type userState struct {
+	User u.User
+}
+
+// userSettings is the stored form of userState, location can't be marshaled as is
+type userSettings struct {
+	Location    string `json:"location"`
+	WindowFloor string `json:"window_floor"`
+	WindowCeil  string `json:"window_ceil"`
+	Language    string `json:"language"`
+}
+
+func (s userState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(userSettings{
+		Location:    s.User.LocationString(),
+		WindowFloor: s.User.WindowFloorString(),
+		WindowCeil:  s.User.WindowCeilString(),
+		Language:    s.User.Language,
+	})
+}
+
+func (s *userState) UnmarshalJSON(data []byte) error {
+	var settings userSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+
+	if err := s.User.SetLocation(settings.Location); err != nil {
+		return err
+	}
+
+	if err := s.User.SetWindowFloor(settings.WindowFloor); err != nil {
+		return err
+	}
+
+	if err := s.User.SetWindowCeil(settings.WindowCeil); err != nil {
+		return err
+	}
+
+	// saved before language was asked for
+	if settings.Language == "" {
+		return nil
+	}
+	return s.User.SetLanguage(settings.Language)
+}
+
 func NewChatAddUser(chat *Chat) *ChatAddUser {
 	c := &ChatAddUser{chat}
 
@@ -25,7 +72,6 @@ func NewChatAddUser(chat *Chat) *ChatAddUser {
 }
 
 func (c *ChatAddUser) chat() {
-	defer close(c.inCh)
 	defer c.deleteChat()
 
 	user := u.NewUser(
@@ -34,61 +80,102 @@ func (c *ChatAddUser) chat() {
 		u.WithIsRunning(true),
 	)
 
-	stage := "location"
-	c.SendMessage(fmt.Sprintf(domain.ReplySetLocation, user.LocationString()), domain.KbLocations)
-
-	for msg := range c.inCh {
-		if c.isCancel(msg) {
-			c.SendMessage(domain.ReplyCancel, nil)
-			return
-		}
-
-		switch stage {
-		case "location":
-			if !c.skipped(msg) {
-				if err := user.SetLocation(msg); err != nil {
-					c.log.Error("failed to set location", zap.Error(err))
-					c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingLocation, err).Error(), domain.KbLocations)
-					break
-				}
-			}
-
-			c.SendMessage(fmt.Sprintf(domain.ReplySetWindowFloor, user.WindowFloorString()), domain.KbWindowFloor)
-			stage = "floor"
-
-		case "floor":
-			if !c.skipped(msg) {
-				if err := user.SetWindowFloor(msg); err != nil {
-					c.log.Error("failed to set floor time", zap.Error(err))
-					c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingTime, err).Error(), nil)
-					break
-				}
-			}
+	converse(c.Chat, c.userFlow(flowAddUser, c.createUser), &userState{User: user})
+}
 
-			c.SendMessage(fmt.Sprintf(domain.ReplySetWindowCeil, user.WindowCeilString()), domain.KbWindowCeil)
-			stage = "ceil"
+func (c *Chat) createUser(s *userState) {
+	if _, err := c.db.CreateUser(context.Background(), s.User); err != nil {
+		c.log.Error("failed to create user", zap.Error(err))
+		c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorCreatingUser), err).Error(), nil)
+		return
+	}
 
-		case "ceil":
-			if !c.skipped(msg) {
-				if err := user.SetWindowCeil(msg); err != nil {
-					c.log.Error("failed to set ceil time", zap.Error(err))
-					c.SendMessage(domain.ReplyErrorParsingTime, nil)
-					break
-				}
-			}
+	c.SendMessage(domain.ReplyUserUpdated, nil)
+}
 
-			if _, err := c.db.CreateUser(context.Background(), user); err != nil {
-				c.log.Error("failed to create user", zap.Error(err))
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorCreatingUser, err).Error(), nil)
-				return
+// userFlow walks through user profile settings, shared by user creation and update
+func (c *Chat) userFlow(name string, done func(s *userState)) flow[userState] {
+	return flow[userState]{
+		name: name,
+		steps: []step[userState]{
+			{
+				name: "location",
+				prompt: func(s *userState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplySetLocation), s.User.LocationString()), domain.KbLocations
+				},
+				apply: func(s *userState, msg string) error {
+					if err := s.User.SetLocation(msg); err != nil {
+						c.log.Error("failed to set location", zap.Error(err))
+						return fmt.Errorf(c.tr(domain.ReplyErrorParsingLocation), err)
+					}
+					return nil
+				},
+				skip: skipStep[userState],
+			},
+			{
+				name: "floor",
+				prompt: func(s *userState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplySetWindowFloor), s.User.WindowFloorString()), domain.KbWindowFloor
+				},
+				apply: func(s *userState, msg string) error {
+					if err := s.User.SetWindowFloor(msg); err != nil {
+						c.log.Error("failed to set floor time", zap.Error(err))
+						return fmt.Errorf(c.tr(domain.ReplyErrorParsingTime), err)
+					}
+					return nil
+				},
+				// TOD: later rewrite windows using duration to evoid bugs
+				// re-parsing the kept value is a temporary fix
+				skip: func(s *userState) error {
+					if err := s.User.SetWindowFloor(s.User.WindowFloorString()); err != nil {
+						c.log.Error("failed to set default floor time", zap.Error(err))
+					}
+					return nil
+				},
+			},
+			{
+				name: "ceil",
+				prompt: func(s *userState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplySetWindowCeil), s.User.WindowCeilString()), domain.KbWindowCeil
+				},
+				apply: func(s *userState, msg string) error {
+					if err := s.User.SetWindowCeil(msg); err != nil {
+						c.log.Error("failed to set ceil time", zap.Error(err))
+						return fmt.Errorf(c.tr(domain.ReplyErrorParsingTime), err)
+					}
+					return nil
+				},
+				skip: func(s *userState) error {
+					if err := s.User.SetWindowCeil(s.User.WindowCeilString()); err != nil {
+						c.log.Error("failed to set default ceil time", zap.Error(err))
+					}
+					return nil
+				},
+			},
+			{
+				name: "language",
+				prompt: func(s *userState) (string, domain.Keyboard) {
+					lang := c.tr(domain.ReplyLanguageAuto)
+					if s.User.Language != "" {
+						lang = domain.LanguageName(s.User.Language)
+					}
+					return fmt.Sprintf(c.tr(domain.ReplySetLanguage), lang), domain.KbLanguages
+				},
+				apply: func(s *userState, msg string) error {
+					if err := s.User.SetLanguage(msg); err != nil {
+						return fmt.Errorf(c.tr(domain.ReplyErrorParsingLanguage), err)
+					}
+					return nil
+				},
+				skip: skipStep[userState],
+			},
+		},
+		done: func(s *userState) {
+			// the rest of the session speaks the chosen language, telegram one is picked up with the next message
+			if s.User.Language != "" {
+				c.lang = s.User.Language
 			}
-
-			c.SendMessage(domain.ReplyUserUpdated, nil)
-			return
-
-		default:
-			c.log.Error("unknown stage", zap.String("stage", stage))
-			return
-		}
+			done(s)
+		},
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/vedomirr/l"
 	"github.com/vedomirr/remindista/internal/domain"
@@ -12,45 +14,110 @@ import (
 	"go.uber.org/zap"
 )
 
+// defaultIdleTimeout is used when chat is created without idle timeout
+const defaultIdleTimeout = 15 * time.Minute
+
 type Chat struct {
 	chatId, tgId int64
 
+	// lang is the language replies are translated into
+	lang string
+
 	inCh     chan string
+	docCh    chan domain.Document
 	outCh    chan domain.Message
-	deleteCh chan int64
+	deleteCh chan *Chat
+
+	// closeCh is closed to end the session from outside, done is closed once session goroutine is over
+	closeCh   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	doneOnce  sync.Once
+
+	idleTimeout time.Duration
+
+	// resume is a saved conversation the flow continues instead of starting over
+	resume *domain.Conversation
 
 	db repository
 
 	log *zap.Logger
 }
 
-func NewChat(chatId, tgId int64, outCh chan domain.Message, deleteCh chan int64, db repository) *Chat {
+func NewChat(chatId, tgId int64, lang string, outCh chan domain.Message, deleteCh chan *Chat, db repository, idleTimeout time.Duration) *Chat {
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+
 	return &Chat{
 		chatId: chatId,
 		tgId:   tgId,
+		lang:   lang,
 
 		inCh:     make(chan string),
+		docCh:    make(chan domain.Document),
 		outCh:    outCh,
 		deleteCh: deleteCh,
 
+		closeCh: make(chan struct{}),
+		done:    make(chan struct{}),
+
+		idleTimeout: idleTimeout,
+
 		db: db,
 
 		log: l.Logger(),
 	}
 }
 
+func (c *Chat) ChatId() int64 {
+	return c.chatId
+}
+
+// Close ends the session without notifying user, e.g. when it's replaced by a new command
+func (c *Chat) Close() {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+}
+
+// Done is closed once the session is over and won't accept input anymore
+func (c *Chat) Done() <-chan struct{} {
+	return c.done
+}
+
 func (c *Chat) SendMessage(text string, keyboard domain.Keyboard) {
-	c.outCh <- domain.Message{UserName: "Remindista", ChatId: c.chatId, Text: text, Keyboard: keyboard}
+	c.outCh <- domain.Message{UserName: "Remindista", ChatId: c.chatId, Lang: c.lang, Text: text, Keyboard: keyboard}
+}
+
+// EditMessage replaces text and buttons of the message sent before, no keyboard removes the buttons
+func (c *Chat) EditMessage(messageId int, text string, keyboard domain.Keyboard) {
+	c.outCh <- domain.Message{UserName: "Remindista", ChatId: c.chatId, MessageId: messageId, Lang: c.lang, Text: text, Keyboard: keyboard}
+}
+
+func (c *Chat) SendDocument(caption string, document domain.Document) {
+	c.outCh <- domain.Message{UserName: "Remindista", ChatId: c.chatId, Lang: c.lang, Text: caption, Document: &document}
+}
+
+// tr translates reply into the chat language, formatted replies are translated before formatting
+func (c *Chat) tr(s string) string {
+	return domain.Tr(c.lang, s)
 }
 
 func (c *Chat) PassInput(input string) {
-	c.inCh <- input
+	select {
+	case c.inCh <- input:
+	case <-c.done:
+	}
+}
+
+func (c *Chat) PassDocument(document domain.Document) {
+	select {
+	case c.docCh <- document:
+	case <-c.done:
+	}
 }
 
 //nolint:golint,unused
 func (c *Chat) chat() {
-	defer close(c.inCh)
-
 	c.SendMessage("Started new chat!", nil)
 
 	for msg := range c.inCh {
@@ -65,8 +132,10 @@ func (c *Chat) chat() {
 	c.deleteChat()
 }
 
+// deleteChat marks the session as over and asks updater to forget it
 func (c *Chat) deleteChat() {
-	c.deleteCh <- c.chatId
+	c.doneOnce.Do(func() { close(c.done) })
+	c.deleteCh <- c
 }
 
 func (c *Chat) skipped(s string) bool {
@@ -81,17 +150,14 @@ func (c *Chat) getUser() (user u.User, err error) {
 		return user, errors.New("failed to get user")
 	}
 
-	// update chat id associated with this user if needed
-	if user.ChatId != c.chatId {
-		user.ChatId = c.chatId
-		if _, err = c.db.UpdateUser(context.Background(), user); err != nil {
-			return user, err
-		}
-	}
-
+	// user's chat id is the private one reminders are delivered to, chat sessions only run there
 	return user, nil
 }
 
 func (c *Chat) isCancel(msg string) bool {
 	return strings.ToLower(msg) == "cancel"
 }
+
+func (c *Chat) isBack(msg string) bool {
+	return strings.ToLower(strings.TrimSpace(msg)) == "back"
+}
@@ -0,0 +1,95 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+
+	"go.uber.org/zap"
+)
+
+type ChatCalendar struct {
+	*Chat
+	baseUrl string
+}
+
+func NewChatCalendar(chat *Chat, baseUrl string) *ChatCalendar {
+	c := &ChatCalendar{Chat: chat, baseUrl: baseUrl}
+
+	go c.chat()
+
+	return c
+}
+
+func (c *ChatCalendar) chat() {
+	defer c.deleteChat()
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	// issue a token on first use
+	if user.CalendarToken == "" {
+		if err := c.rotateToken(&user); err != nil {
+			c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorCalendarLink), err).Error(), nil)
+			return
+		}
+	}
+
+	converse(c.Chat, c.flow(), &calendarState{User: user})
+}
+
+type calendarState struct {
+	User u.User `json:"-"`
+}
+
+func (c *ChatCalendar) flow() flow[calendarState] {
+	return flow[calendarState]{
+		name: flowCalendar,
+		steps: []step[calendarState]{
+			{
+				name: "action",
+				prompt: func(s *calendarState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplyCalendarLink), c.feedUrl(s.User)), domain.KbCalendar
+				},
+				apply: func(s *calendarState, msg string) error {
+					if msg != "rotate" {
+						c.SendMessage(domain.ReplyDone, nil)
+						return nil
+					}
+
+					if err := c.rotateToken(&s.User); err != nil {
+						return abort(fmt.Errorf(c.tr(domain.ReplyErrorCalendarLink), err).Error())
+					}
+
+					c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyCalendarLinkRotated), c.feedUrl(s.User)), nil)
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func (c *ChatCalendar) rotateToken(user *u.User) error {
+	if err := user.RotateCalendarToken(); err != nil {
+		c.log.Error("failed to generate calendar token", zap.Int("user_id", user.Id), zap.Error(err))
+		return err
+	}
+
+	if _, err := c.db.UpdateUserCalendarToken(context.Background(), user.Id, user.CalendarToken); err != nil {
+		c.log.Error("failed to update calendar token", zap.Int("user_id", user.Id), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (c *ChatCalendar) feedUrl(user u.User) string {
+	return fmt.Sprintf("%s/calendar/%s.ics", strings.TrimSuffix(c.baseUrl, "/"), user.CalendarToken)
+}
@@ -0,0 +1,310 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// stepDone is a transition target that finishes the conversation
+const stepDone = "done"
+
+// step is a single stage of a conversation, operating on the flow state T
+type step[T any] struct {
+	name string
+
+	// prompt is sent when the step is entered and repeated along with validation errors
+	prompt func(state *T) (text string, keyboard domain.Keyboard)
+
+	// apply validates input and stores it in state, returned error text is sent back to user
+	apply func(state *T, input string) error
+
+	// document handles an uploaded file, steps without it don't accept files
+	document func(state *T, document domain.Document) error
+
+	// skip is called when user sends `skip`, steps without it can't be skipped
+	skip func(state *T) error
+
+	// next picks the following step name, by default it is the next declared step
+	next func(state *T) string
+}
+
+// flow is a declared conversation: steps run from the first one until a transition to stepDone
+type flow[T any] struct {
+	name  string
+	steps []step[T]
+
+	// start is the step conversation begins with, the first declared one if empty
+	start string
+
+	// done is called once the last step is completed
+	done func(state *T)
+
+	// restore rebuilds state values not kept in storage after the conversation is resumed
+	restore func(state *T)
+}
+
+// abortError stops the conversation after its text is sent to user
+type abortError struct{ reply string }
+
+func (e abortError) Error() string { return e.reply }
+
+func abort(reply string) error {
+	return abortError{reply: reply}
+}
+
+// skipStep is a skip handler for optional steps that keep the current value
+func skipStep[T any](*T) error { return nil }
+
+// converse runs the flow on the chat's input until it's done, cancelled, closed or idle for too long.
+// Steps passed are kept in history, so user can go back keeping values already entered.
+// Progress is saved after every step, conversation closed from outside stays saved to be resumed.
+func converse[T any](c *Chat, f flow[T], state *T) {
+	if len(f.steps) == 0 {
+		return
+	}
+
+	cur := max(f.index(f.start), 0)
+	var history []int
+
+	closed := false
+	defer func() {
+		if !closed {
+			c.forgetConversation()
+		}
+	}()
+
+	save := func() {
+		names := make([]string, 0, len(history))
+		for _, idx := range history {
+			names = append(names, f.steps[idx].name)
+		}
+		c.saveConversation(f.name, f.steps[cur].name, names, state)
+	}
+
+	keyboard := func(kb domain.Keyboard) domain.Keyboard {
+		if len(history) > 0 {
+			return kb.WithBack()
+		}
+		return kb
+	}
+
+	prompt := func() {
+		text, kb := f.steps[cur].prompt(state)
+		c.prompt(text, keyboard(kb))
+	}
+
+	// resumed conversation waits for input on the saved step, its prompt was sent before restart
+	if ok := resume(c, f, state, &cur, &history); !ok {
+		prompt()
+		save()
+	}
+
+	idle := time.NewTimer(c.idleTimeout)
+	defer idle.Stop()
+
+	for {
+		var err error
+		s := f.steps[cur]
+
+		select {
+		case <-c.closeCh:
+			closed = true
+			return
+
+		case <-idle.C:
+			c.SendMessage(domain.ReplySessionTimedOut, nil)
+			return
+
+		case msg := <-c.inCh:
+			idle.Reset(c.idleTimeout)
+
+			switch {
+			case c.isCancel(msg):
+				c.SendMessage(domain.ReplyCancel, nil)
+				return
+
+			case c.isBack(msg) && len(history) == 0:
+				c.SendMessage(domain.ReplyCannotGoBack, nil)
+				prompt()
+				continue
+
+			case c.isBack(msg):
+				cur, history = history[len(history)-1], history[:len(history)-1]
+				prompt()
+				save()
+				continue
+
+			case c.skipped(msg) && s.skip == nil:
+				c.SendMessage(domain.ReplyCannotSkip, nil)
+				prompt()
+				continue
+
+			case c.skipped(msg):
+				err = s.skip(state)
+
+			case s.apply == nil:
+				c.SendMessage(domain.ReplySendFile, nil)
+				continue
+
+			default:
+				err = s.apply(state, msg)
+			}
+
+		case doc := <-c.docCh:
+			idle.Reset(c.idleTimeout)
+
+			if s.document == nil {
+				c.SendMessage(domain.ReplyUnexpectedFile, nil)
+				continue
+			}
+			err = s.document(state, doc)
+		}
+
+		var aborted abortError
+		if errors.As(err, &aborted) {
+			c.SendMessage(aborted.reply, nil)
+			return
+		} else if err != nil {
+			_, kb := s.prompt(state)
+			c.SendMessage(err.Error(), keyboard(kb))
+			continue
+		}
+
+		next := f.next(cur, state)
+		if next == stepDone {
+			if f.done != nil {
+				f.done(state)
+			}
+			return
+		}
+
+		idx := f.index(next)
+		if idx < 0 {
+			c.log.Error("unknown step", zap.String("flow", f.name), zap.String("step", next))
+			return
+		}
+
+		history = advance(history, cur, idx)
+		cur = idx
+
+		prompt()
+		save()
+	}
+}
+
+// advance records the step being left, returning to a step seen before drops history after it
+func advance(history []int, from, to int) []int {
+	if from == to {
+		return history
+	}
+
+	for i, idx := range history {
+		if idx == to {
+			return history[:i]
+		}
+	}
+
+	return append(history, from)
+}
+
+func (f flow[T]) next(cur int, state *T) string {
+	if s := f.steps[cur]; s.next != nil {
+		return s.next(state)
+	}
+
+	if cur+1 < len(f.steps) {
+		return f.steps[cur+1].name
+	}
+
+	return stepDone
+}
+
+func (f flow[T]) index(name string) int {
+	for i, s := range f.steps {
+		if s.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Chat) saveConversation(flow, step string, history []string, state any) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		c.log.Error("failed to marshal conversation state", zap.String("flow", flow), zap.Error(err))
+		return
+	}
+
+	if err := c.db.SaveConversation(context.Background(), domain.Conversation{
+		ChatId:     c.chatId,
+		TelegramId: c.tgId,
+		Flow:       flow,
+		Step:       step,
+		History:    history,
+		State:      data,
+	}); err != nil {
+		c.log.Error("failed to save conversation", zap.Int64("chat id", c.chatId), zap.Error(err))
+	}
+}
+
+// resume positions the flow on the step saved before restart and loads its state
+func resume[T any](c *Chat, f flow[T], state *T, cur *int, history *[]int) bool {
+	conv := c.resume
+	if conv == nil {
+		return false
+	}
+	c.resume = nil
+
+	if conv.Flow != f.name {
+		c.log.Error("resumed conversation flow mismatch", zap.String("saved", conv.Flow), zap.String("flow", f.name))
+		return false
+	}
+
+	idx := f.index(conv.Step)
+	if idx < 0 {
+		c.log.Error("resumed conversation step not found", zap.String("flow", f.name), zap.String("step", conv.Step))
+		return false
+	}
+
+	steps := make([]int, 0, len(conv.History))
+	for _, name := range conv.History {
+		i := f.index(name)
+		if i < 0 {
+			c.log.Error("resumed conversation step not found", zap.String("flow", f.name), zap.String("step", name))
+			return false
+		}
+		steps = append(steps, i)
+	}
+
+	// values not kept in storage stay as the flow initialized them
+	restored := *state
+	if err := json.Unmarshal(conv.State, &restored); err != nil {
+		c.log.Error("failed to unmarshal conversation state", zap.String("flow", f.name), zap.Error(err))
+		return false
+	}
+
+	*state, *cur, *history = restored, idx, steps
+	if f.restore != nil {
+		f.restore(state)
+	}
+
+	return true
+}
+
+func (c *Chat) forgetConversation() {
+	if _, err := c.db.DeleteConversation(context.Background(), c.chatId); err != nil {
+		c.log.Error("failed to delete conversation", zap.Int64("chat id", c.chatId), zap.Error(err))
+	}
+}
+
+func (c *Chat) prompt(text string, keyboard domain.Keyboard) {
+	if text != "" {
+		c.SendMessage(text, keyboard)
+	}
+}
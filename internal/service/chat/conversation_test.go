@@ -0,0 +1,666 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+)
+
+// fakeRepo implements only the calls flows under test make, others panic on nil interface
+type fakeRepo struct {
+	repository
+
+	user    u.User
+	created []r.Reminder
+	deleted int
+	tags    []r.TagCount
+	renamed [][2]string
+	rmds    []r.Reminder
+	updated []r.Reminder
+
+	mu   sync.Mutex
+	conv *domain.Conversation
+}
+
+func (f *fakeRepo) SaveConversation(_ context.Context, conv domain.Conversation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.conv = &conv
+	return nil
+}
+
+func (f *fakeRepo) DeleteConversation(context.Context, int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.conv = nil
+	return 1, nil
+}
+
+func (f *fakeRepo) savedConversation() *domain.Conversation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conv
+}
+
+func (f *fakeRepo) GetUserByTelegramId(context.Context, int64) (u.User, error) {
+	return f.user, nil
+}
+
+func (f *fakeRepo) CreateReminder(_ context.Context, rmd r.Reminder) (int, error) {
+	f.created = append(f.created, rmd)
+	return len(f.created), nil
+}
+
+func (f *fakeRepo) DeleteRemindersByUserId(context.Context, int) (int, error) {
+	f.deleted++
+	return 3, nil
+}
+
+// strangersReminderId belongs to another user, fake repo scopes access like the real one does
+const strangersReminderId = 1000
+
+func (f *fakeRepo) GetReminder(_ context.Context, userId int, id int) (r.Reminder, error) {
+	if id == strangersReminderId || userId != f.user.Id {
+		return r.Reminder{}, domain.ErrorForbidden
+	}
+	return r.Reminder{Id: id, Number: 5, UserId: f.user.Id, Text: "Goroutines"}, nil
+}
+
+func (f *fakeRepo) DeleteReminder(_ context.Context, userId int, id int) (int, error) {
+	if id == strangersReminderId || userId != f.user.Id {
+		return 0, domain.ErrorForbidden
+	}
+	f.deleted++
+	return 1, nil
+}
+
+func (f *fakeRepo) GetDeletedRemindersByUserId(context.Context, int, int) ([]r.Reminder, error) {
+	if f.deleted == 0 {
+		return nil, nil
+	}
+	return []r.Reminder{{Id: 70, Number: 7, UserId: f.user.Id}}, nil
+}
+
+// SearchReminders finds a single reminder for "sql" query
+func (f *fakeRepo) SearchReminders(_ context.Context, _ int, query string, _ int) ([]r.SearchHit, error) {
+	if query != "sql" {
+		return nil, nil
+	}
+	return []r.SearchHit{{Reminder: r.Reminder{Id: 1, Text: "joins"}, TextHeadline: "joins"}}, nil
+}
+
+func (f *fakeRepo) GetTagCounts(context.Context, int) ([]r.TagCount, error) {
+	return f.tags, nil
+}
+
+func (f *fakeRepo) GetRemindersByUserId(context.Context, int) ([]r.Reminder, error) {
+	return f.rmds, nil
+}
+
+func (f *fakeRepo) UpdateReminder(_ context.Context, rmd r.Reminder) (int, error) {
+	f.updated = append(f.updated, rmd)
+	return 1, nil
+}
+
+func (f *fakeRepo) RenameTag(_ context.Context, _ int, from, to string) (int, error) {
+	f.renamed = append(f.renamed, [2]string{from, to})
+	return 2, nil
+}
+
+func newTestChat(db repository) (*Chat, chan domain.Message, chan *Chat) {
+	outCh, deleteCh := make(chan domain.Message, 100), make(chan *Chat, 1)
+	return NewChat(1, 1, "en", outCh, deleteCh, db, time.Minute), outCh, deleteCh
+}
+
+// finish waits for the conversation to end and returns everything it sent
+func finish(t *testing.T, outCh chan domain.Message, deleteCh chan *Chat) (replies []string) {
+	t.Helper()
+
+	select {
+	case <-deleteCh:
+	case <-time.After(time.Second):
+		t.Fatal("conversation didn't finish")
+	}
+
+	for {
+		select {
+		case msg := <-outCh:
+			replies = append(replies, msg.Text)
+		default:
+			return replies
+		}
+	}
+}
+
+type toyState struct {
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Finished bool   `json:"-"`
+}
+
+func toyFlow() flow[toyState] {
+	return flow[toyState]{
+		name: "toy",
+		steps: []step[toyState]{
+			{
+				name:   "name",
+				prompt: func(*toyState) (string, domain.Keyboard) { return "name?", nil },
+				apply: func(s *toyState, msg string) error {
+					if msg == "" {
+						return errors.New("empty name")
+					}
+					s.Name = msg
+					return nil
+				},
+			},
+			{
+				name:   "color",
+				prompt: func(*toyState) (string, domain.Keyboard) { return "color?", nil },
+				apply: func(s *toyState, msg string) error {
+					if msg == "black" {
+						return abort("no black")
+					}
+					s.Color = msg
+					return nil
+				},
+				skip: skipStep[toyState],
+			},
+		},
+		done: func(s *toyState) { s.Finished = true },
+	}
+}
+
+func TestConverse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   []string
+		want    toyState
+		replies []string
+	}{
+		{
+			name:    "complete",
+			input:   []string{"box", "red"},
+			want:    toyState{Name: "box", Color: "red", Finished: true},
+			replies: []string{"name?", "color?"},
+		},
+		{
+			name:    "validation error repeats step",
+			input:   []string{"", "box", "red"},
+			want:    toyState{Name: "box", Color: "red", Finished: true},
+			replies: []string{"name?", "empty name", "color?"},
+		},
+		{
+			name:    "skip",
+			input:   []string{"skip", "box", "skip"},
+			want:    toyState{Name: "box", Finished: true},
+			replies: []string{"name?", domain.ReplyCannotSkip, "name?", "color?"},
+		},
+		{
+			name:    "back",
+			input:   []string{"back", "box", "back", "boxy", "skip"},
+			want:    toyState{Name: "boxy", Finished: true},
+			replies: []string{"name?", domain.ReplyCannotGoBack, "name?", "color?", "name?", "color?"},
+		},
+		{
+			name:    "cancel",
+			input:   []string{"box", "cancel"},
+			want:    toyState{Name: "box"},
+			replies: []string{"name?", "color?", domain.ReplyCancel},
+		},
+		{
+			name:    "abort",
+			input:   []string{"box", "black"},
+			want:    toyState{Name: "box"},
+			replies: []string{"name?", "color?", "no black"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, outCh, deleteCh := newTestChat(&fakeRepo{})
+
+			state := &toyState{}
+			go func() {
+				converse(c, toyFlow(), state)
+				c.deleteChat()
+			}()
+
+			for _, msg := range tc.input {
+				c.PassInput(msg)
+			}
+
+			replies := finish(t, outCh, deleteCh)
+
+			if *state != tc.want {
+				t.Errorf("state = %+v, want %+v", *state, tc.want)
+			}
+
+			if len(replies) != len(tc.replies) {
+				t.Fatalf("replies = %q, want %q", replies, tc.replies)
+			}
+			for i := range replies {
+				if replies[i] != tc.replies[i] {
+					t.Errorf("reply %d = %q, want %q", i, replies[i], tc.replies[i])
+				}
+			}
+		})
+	}
+}
+
+func TestConverse_Lifecycle(t *testing.T) {
+	t.Run("idle timeout", func(t *testing.T) {
+		outCh, deleteCh := make(chan domain.Message, 100), make(chan *Chat, 1)
+		c := NewChat(1, 1, "en", outCh, deleteCh, &fakeRepo{}, 10*time.Millisecond)
+
+		go func() {
+			converse(c, toyFlow(), &toyState{})
+			c.deleteChat()
+		}()
+
+		replies := finish(t, outCh, deleteCh)
+		if last := replies[len(replies)-1]; last != domain.ReplySessionTimedOut {
+			t.Errorf("last reply = %q, want %q", last, domain.ReplySessionTimedOut)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		c, outCh, deleteCh := newTestChat(&fakeRepo{})
+
+		go func() {
+			converse(c, toyFlow(), &toyState{})
+			c.deleteChat()
+		}()
+
+		c.Close()
+
+		if replies := finish(t, outCh, deleteCh); len(replies) != 1 {
+			t.Errorf("replies = %q, want only the first prompt", replies)
+		}
+
+		// input passed to a finished session must not block
+		c.PassInput("box")
+	})
+}
+
+func TestConverse_Resume(t *testing.T) {
+	db := &fakeRepo{}
+	c, outCh, deleteCh := newTestChat(db)
+
+	go func() {
+		converse(c, toyFlow(), &toyState{})
+		c.deleteChat()
+	}()
+
+	c.PassInput("box")
+	c.Close()
+	finish(t, outCh, deleteCh)
+
+	conv := db.savedConversation()
+	if conv == nil || conv.Flow != "toy" || conv.Step != "color" {
+		t.Fatalf("saved conversation = %+v, want toy flow on color step", conv)
+	}
+
+	// restarted chat continues from the saved step
+	c, outCh, deleteCh = newTestChat(db)
+	c.resume = conv
+
+	state := &toyState{}
+	go func() {
+		converse(c, toyFlow(), state)
+		c.deleteChat()
+	}()
+
+	c.PassInput("back")
+	c.PassInput("box")
+	c.PassInput("red")
+
+	replies := finish(t, outCh, deleteCh)
+
+	want := toyState{Name: "box", Color: "red", Finished: true}
+	if *state != want {
+		t.Errorf("state = %+v, want %+v", *state, want)
+	}
+
+	// prompt isn't repeated on resume, back leads to the step saved in history
+	if len(replies) == 0 || replies[0] != "name?" {
+		t.Errorf("replies = %q, want to start with going back to name", replies)
+	}
+
+	if conv := db.savedConversation(); conv != nil {
+		t.Errorf("finished conversation is still saved: %+v", conv)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		history  []int
+		from, to int
+		want     []int
+	}{
+		{"forward", []int{0}, 1, 2, []int{0, 1}},
+		{"same step", []int{0}, 1, 1, []int{0}},
+		{"back to visited step", []int{0, 1, 2}, 3, 1, []int{0}},
+		{"back to first step", []int{0, 1}, 2, 0, []int{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := advance(tc.history, tc.from, tc.to); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("advance() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestChatAddReminder(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+	c, outCh, deleteCh := newTestChat(db)
+
+	chat := NewChatAddReminder(c, "")
+	for _, msg := range []string{"What is a gorutine?", "back", "What is a goroutine?", "skip", "lightweight thread", "back", "back", "skip", "skip", "3 days"} {
+		chat.PassInput(msg)
+	}
+
+	finish(t, outCh, deleteCh)
+
+	if len(db.created) != 1 {
+		t.Fatalf("created %d reminders, want 1", len(db.created))
+	}
+
+	rmd := db.created[0]
+	if rmd.Text != "What is a goroutine?" || rmd.Tag != "" || rmd.Prompt != "lightweight thread" || rmd.Frequency != 72*time.Hour {
+		t.Errorf("created reminder = %+v", rmd)
+	}
+}
+
+func TestChatAddReminder_QuickAdd(t *testing.T) {
+	t.Run("created right away", func(t *testing.T) {
+		db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+		c, outCh, deleteCh := newTestChat(db)
+
+		NewChatAddReminder(c, "Learn window functions every 3 days #sql")
+		finish(t, outCh, deleteCh)
+
+		if len(db.created) != 1 {
+			t.Fatalf("created %d reminders, want 1", len(db.created))
+		}
+		if rmd := db.created[0]; rmd.Text != "Learn window functions" || rmd.Tag != "#sql" || rmd.Frequency != 72*time.Hour {
+			t.Errorf("created reminder = %+v", rmd)
+		}
+	})
+
+	t.Run("asks for missing frequency", func(t *testing.T) {
+		db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+		c, outCh, deleteCh := newTestChat(db)
+
+		chat := NewChatAddReminder(c, "Learn window functions #sql")
+		chat.PassInput("2 days")
+
+		replies := finish(t, outCh, deleteCh)
+
+		if len(replies) == 0 || replies[0] != domain.ReplySetReminderFrequency {
+			t.Errorf("replies = %q, want frequency asked first", replies)
+		}
+		if len(db.created) != 1 || db.created[0].Tag != "#sql" || db.created[0].Frequency != 48*time.Hour {
+			t.Errorf("created reminders = %+v", db.created)
+		}
+	})
+
+	t.Run("stops at unreadable line", func(t *testing.T) {
+		db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+		c, outCh, deleteCh := newTestChat(db)
+
+		NewChatAddReminder(c, "Learn window functions every 0 days")
+		replies := finish(t, outCh, deleteCh)
+
+		if len(replies) != 1 || !strings.HasPrefix(replies[0], "Couldn't read the reminder") {
+			t.Errorf("replies = %q, want only the error", replies)
+		}
+		if len(db.created) != 0 {
+			t.Errorf("created reminders = %+v", db.created)
+		}
+		if conv := db.savedConversation(); conv != nil {
+			t.Errorf("saved conversation = %+v, want none", conv)
+		}
+	})
+}
+
+func TestChatDeleteReminder_AllFromArgs(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+	c, outCh, deleteCh := newTestChat(db)
+
+	chat := NewChatDeleteReminder(c, "all")
+	chat.PassInput("yes")
+
+	replies := finish(t, outCh, deleteCh)
+
+	if db.deleted != 1 {
+		t.Errorf("deleted all %d times, want 1", db.deleted)
+	}
+	if len(replies) == 0 || replies[0] != domain.ReplyConfirmDeletingAll {
+		t.Errorf("replies = %q, want confirmation asked first", replies)
+	}
+}
+
+func TestChatDeleteReminderById_StrikesCard(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+	c, outCh, deleteCh := newTestChat(db)
+
+	chat := NewChatDeleteReminderById(c, 50, 42)
+	chat.PassInput("yes")
+
+	<-deleteCh
+
+	var card *domain.Message
+	for len(outCh) > 0 {
+		if msg := <-outCh; msg.MessageId != 0 {
+			card = &msg
+		}
+	}
+
+	if card == nil {
+		t.Fatal("card wasn't edited")
+	}
+	if card.MessageId != 42 || !strings.Contains(card.Text, "~Goroutines~") {
+		t.Errorf("edited card = %+v, want struck through message 42", card)
+	}
+	if want := domain.CallbackUndoDelete + " 5"; len(card.Keyboard) != 1 || card.Keyboard[0][0].Val != want {
+		t.Errorf("card keyboard = %v, want single %q button", card.Keyboard, want)
+	}
+}
+
+func TestChatDeleteReminderById_StrangersReminder(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+	c, outCh, deleteCh := newTestChat(db)
+
+	chat := NewChatDeleteReminderById(c, strangersReminderId, 42)
+	chat.PassInput("yes")
+
+	replies := finish(t, outCh, deleteCh)
+
+	if db.deleted != 0 {
+		t.Errorf("deleted %d reminders of another user", db.deleted)
+	}
+	if len(replies) == 0 || replies[len(replies)-1] != domain.ReplyNoSuchId {
+		t.Errorf("replies = %q, want %q last", replies, domain.ReplyNoSuchId)
+	}
+}
+
+func TestChatDeleteReminder_BackToMode(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+	c, outCh, deleteCh := newTestChat(db)
+
+	chat := NewChatDeleteReminder(c, "")
+	for _, msg := range []string{"stop", "all", "no", "all", "yes"} {
+		chat.PassInput(msg)
+	}
+
+	replies := finish(t, outCh, deleteCh)
+
+	if db.deleted != 1 {
+		t.Errorf("deleted all %d times, want 1", db.deleted)
+	}
+
+	want := []string{
+		domain.ReplySetMode,
+		domain.ReplyUnknown + " " + domain.ReplyModes,
+		domain.ReplyConfirmDeletingAll,
+		domain.ReplyCancel,
+		domain.ReplyModes,
+		domain.ReplyConfirmDeletingAll,
+		fmt.Sprintf(domain.ReplyDeletedMultiple, 3),
+	}
+
+	if len(replies) != len(want) {
+		t.Fatalf("replies = %q, want %q", replies, want)
+	}
+	for i := range replies {
+		if replies[i] != want[i] {
+			t.Errorf("reply %d = %q, want %q", i, replies[i], want[i])
+		}
+	}
+}
+
+func TestChatDeleteReminder_Undo(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+	c, outCh, deleteCh := newTestChat(db)
+
+	chat := NewChatDeleteReminder(c, "")
+	for _, msg := range []string{"all", "yes"} {
+		chat.PassInput(msg)
+	}
+
+	<-deleteCh
+
+	var last domain.Message
+	for len(outCh) > 0 {
+		last = <-outCh
+	}
+
+	want := fmt.Sprintf("%s 7", domain.CallbackUndoDelete)
+	if len(last.Keyboard) != 1 || len(last.Keyboard[0]) != 1 || last.Keyboard[0][0].Val != want {
+		t.Errorf("keyboard = %v, want single %q button", last.Keyboard, want)
+	}
+}
+
+func TestChatSearchReminders(t *testing.T) {
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1))}
+
+	t.Run("query found right away", func(t *testing.T) {
+		c, outCh, deleteCh := newTestChat(db)
+		NewChatSearchReminders(c, " sql ")
+
+		replies := finish(t, outCh, deleteCh)
+
+		if len(replies) != 2 || replies[0] != fmt.Sprintf(domain.ReplySearchFound, 1) {
+			t.Errorf("replies = %q, want found message and one card", replies)
+		}
+	})
+
+	t.Run("nothing found asks again", func(t *testing.T) {
+		c, outCh, deleteCh := newTestChat(db)
+		chat := NewChatSearchReminders(c, "go")
+		chat.PassInput("rust")
+		chat.PassInput("sql")
+
+		replies := finish(t, outCh, deleteCh)
+
+		want := []string{domain.ReplyNothingFound, domain.ReplyNothingFound, fmt.Sprintf(domain.ReplySearchFound, 1)}
+		if len(replies) != len(want)+1 {
+			t.Fatalf("replies = %q, want %q and one card", replies, want)
+		}
+		for i := range want {
+			if replies[i] != want[i] {
+				t.Errorf("reply %d = %q, want %q", i, replies[i], want[i])
+			}
+		}
+	})
+}
+
+func TestChatTags(t *testing.T) {
+	tags := []r.TagCount{{Tag: "#go", Count: 2}, {Tag: "#sql", Count: 5}, {Tag: "", Count: 1}}
+
+	testCases := []struct {
+		name  string
+		input []string
+		want  [2]string
+		reply string
+	}{
+		{"rename", []string{"go", "rename", "#golang"}, [2]string{"#go", "#golang"}, domain.ReplyTagRenamed},
+		{"rename into existing tag merges", []string{"#go", "rename", "SQL"}, [2]string{"#go", "#sql"}, domain.ReplyTagsMerged},
+		{"merge", []string{"no_tag", "merge", "#sql"}, [2]string{"", "#sql"}, domain.ReplyTagsMerged},
+		{"merge retries same tag", []string{"#go", "merge", "#go", "#sql"}, [2]string{"#go", "#sql"}, domain.ReplyTagsMerged},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1)), tags: tags}
+			c, outCh, deleteCh := newTestChat(db)
+
+			chat := NewChatTags(c)
+			for _, msg := range tc.input {
+				chat.PassInput(msg)
+			}
+
+			replies := finish(t, outCh, deleteCh)
+
+			if len(db.renamed) != 1 || db.renamed[0] != tc.want {
+				t.Fatalf("renamed = %q, want %q", db.renamed, tc.want)
+			}
+
+			target := r.TagCount{Tag: tc.want[1]}
+			if last := replies[len(replies)-1]; last != fmt.Sprintf(tc.reply, 2, target.TagMdV2("en")) {
+				t.Errorf("last reply = %q", last)
+			}
+		})
+	}
+}
+
+func TestChatTags_Frequency(t *testing.T) {
+	tags := []r.TagCount{{Tag: "#go", Count: 2}}
+	rmds := []r.Reminder{
+		{Id: 1, Tag: "#go", Frequency: time.Hour, IsActive: true},
+		{Id: 2, Tag: "#go", Frequency: time.Hour, IsActive: true},
+		{Id: 3, Tag: "#sql", Frequency: time.Hour, IsActive: true},
+	}
+
+	db := &fakeRepo{user: u.NewUser(u.WithTelegramId(1), u.WithChatId(1)), tags: tags, rmds: rmds}
+	c, outCh, deleteCh := newTestChat(db)
+
+	// zero and negative frequencies can't be scheduled, they're asked again instead
+	chat := NewChatTags(c)
+	for _, msg := range []string{"#go", "frequency", "0 days", "-1 days", "2 days"} {
+		chat.PassInput(msg)
+	}
+
+	replies := finish(t, outCh, deleteCh)
+
+	failed := 0
+	for _, reply := range replies {
+		if strings.HasPrefix(reply, "Couldn't parse frequency") {
+			failed++
+		}
+	}
+	if failed != 2 {
+		t.Errorf("frequency errors = %d, want 2 in %q", failed, replies)
+	}
+
+	if len(db.updated) != 2 {
+		t.Fatalf("updated = %d reminders, want 2", len(db.updated))
+	}
+	for _, rmd := range db.updated {
+		if rmd.Tag != "#go" || rmd.Frequency != 48*time.Hour {
+			t.Errorf("updated reminder %d of tag %s every %v", rmd.Id, rmd.Tag, rmd.Frequency)
+		}
+	}
+}
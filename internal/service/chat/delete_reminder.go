@@ -2,21 +2,30 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/vedomirr/remindista/internal/domain"
 	r "github.com/vedomirr/remindista/internal/entity/reminder"
-
-	"go.uber.org/zap"
+	u "github.com/vedomirr/remindista/internal/entity/user"
 )
 
 type ChatDeleterReminder struct {
 	*Chat
+	target string
+}
+
+type deleteState struct {
+	User u.User       `json:"-"`
+	Tags []r.TagCount `json:"-"`
+	Mode string       `json:"mode"`
 }
 
-func NewChatDeleteReminder(chat *Chat) *ChatDeleterReminder {
-	c := &ChatDeleterReminder{chat}
+// NewChatDeleteReminder deletes right away if reminder number or tag is given, `all` still asks for confirmation.
+// Otherwise it asks what to delete.
+func NewChatDeleteReminder(chat *Chat, target string) *ChatDeleterReminder {
+	c := &ChatDeleterReminder{chat, strings.TrimSpace(target)}
 
 	go c.chat()
 
@@ -24,7 +33,6 @@ func NewChatDeleteReminder(chat *Chat) *ChatDeleterReminder {
 }
 
 func (c *ChatDeleterReminder) chat() {
-	defer close(c.inCh)
 	defer c.deleteChat()
 
 	user, err := c.getUser()
@@ -33,101 +41,172 @@ func (c *ChatDeleterReminder) chat() {
 		return
 	}
 
-	rmd := r.NewReminder()
+	tags, err := c.db.GetTagCounts(context.Background(), user.Id)
+	if err != nil {
+		c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error(), nil)
+		return
+	}
 
-	stage := "mode"
-	c.SendMessage(domain.ReplySetMode, domain.KbSetMode)
+	f := c.flow()
+	state := &deleteState{User: user, Tags: tags}
 
-	for msg := range c.inCh {
-		if c.isCancel(msg) {
-			c.SendMessage(domain.ReplyCancel, nil)
-			return
+	switch {
+	case c.target == "":
+
+	case c.target == "all":
+		state.Mode = "all"
+		f.start = "all"
+
+	default:
+		// numbers go first, so `#12` is a reminder rather than a tag
+		del := c.deleteByTag
+		if rmd := r.NewReminder(); rmd.SetNumber(c.target) == nil {
+			del = c.deleteById
 		}
 
-		switch stage {
-		case "mode":
-
-			switch msg {
-			case "id":
-				c.SendMessage(domain.ReplySendId, domain.KbCancel)
-				stage = "id"
-			case "tag":
-				c.SendMessage(domain.ReplySendTag, domain.KbCancel)
-				stage = "tag"
-			case "all":
-				c.SendMessage(domain.ReplyConfirmDeletingAll, domain.KbYesNo)
-				stage = "all"
-			case "stop":
-				stage = "stop"
-			default:
-				c.SendMessage(domain.ReplyUnknown, nil)
-				c.SendMessage(domain.ReplyModes, domain.KbSetMode)
-			}
-
-		case "id":
-			if err := rmd.HexToId(msg); err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingId, err).Error(), nil)
-				break
-			}
-
-			if nDeleted, err := c.db.DeleteReminder(context.Background(), rmd.Id); err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorDeletingReminder, err).Error(), nil)
-				break
-			} else if nDeleted == 0 {
-				c.SendMessage(domain.ReplyNoSuchId, nil)
-				break
-			}
-
-			c.SendMessage(domain.ReplyDone, nil)
+		err := del(state, c.target)
+		if err == nil {
 			return
+		}
+		c.SendMessage(err.Error(), nil)
+	}
 
-		case "tag":
-			if err := rmd.SetTag(msg); err != nil {
-				c.SendMessage(domain.ReplyErrorParsingTag, domain.KbSkip)
-				break
-			}
-
-			nDeleted, err := c.db.DeleteRemindersByTag(context.Background(), user.Id, rmd.Tag)
-			if err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorDeletingReminder, err).Error(), nil)
-				break
-			} else if nDeleted == 0 {
-				c.SendMessage(domain.ReplyNoSuchTag, domain.KbCancel)
-				break
-			}
-
-			c.SendMessage(fmt.Sprintf(domain.ReplyDeletedMultiple, nDeleted), nil)
-			return
+	converse(c.Chat, f, state)
+}
 
-		case "all":
-			msg := strings.ToLower(msg)
-
-			switch msg {
-			case "yes":
-				nDeleted, err := c.db.DeleteRemindersByUserId(context.Background(), user.Id)
-				if err != nil {
-					c.SendMessage(fmt.Errorf(domain.ReplyErrorDeletingReminder, err).Error(), nil)
-					break
-				} else if nDeleted == 0 {
-					c.SendMessage(domain.ReplyNoReminders, nil)
-					break
-				}
-
-				c.SendMessage(fmt.Sprintf(domain.ReplyDeletedMultiple, nDeleted), nil)
-				return
-
-			case "no":
-				c.SendMessage(domain.ReplyCancel, nil)
-				c.SendMessage(domain.ReplyModes, domain.KbSetMode)
-				stage = "mode"
-
-			default:
-				c.SendMessage(domain.ReplyYesNo, domain.KbYesNo)
-			}
-
-		default:
-			c.log.Error("unknown stage", zap.String("stage", stage))
-			return
+func (c *ChatDeleterReminder) flow() flow[deleteState] {
+	return flow[deleteState]{
+		name: flowDeleteReminder,
+		steps: []step[deleteState]{
+			{
+				name: "mode",
+				prompt: func(s *deleteState) (string, domain.Keyboard) {
+					// coming back from declined "all" mode
+					if s.Mode != "" {
+						return domain.ReplyModes, domain.KbSetMode
+					}
+					return domain.ReplySetMode, domain.KbSetMode
+				},
+				apply: func(s *deleteState, msg string) error {
+					switch msg {
+					case "id", "tag", "all":
+						s.Mode = msg
+						return nil
+					default:
+						return errors.New(domain.ReplyUnknown + " " + domain.ReplyModes)
+					}
+				},
+				next: func(s *deleteState) string { return s.Mode },
+			},
+			{
+				name: "id",
+				prompt: func(*deleteState) (string, domain.Keyboard) {
+					return domain.ReplySendId, domain.KbCancel
+				},
+				apply: c.deleteById,
+				next:  func(*deleteState) string { return stepDone },
+			},
+			{
+				name: "tag",
+				prompt: func(s *deleteState) (string, domain.Keyboard) {
+					return domain.ReplySendTag, c.tagsKeyboard(s.Tags)
+				},
+				apply: c.deleteByTag,
+				next:  func(*deleteState) string { return stepDone },
+			},
+			{
+				name: "all",
+				prompt: func(*deleteState) (string, domain.Keyboard) {
+					return domain.ReplyConfirmDeletingAll, domain.KbYesNo
+				},
+				apply: c.deleteAll,
+				next: func(s *deleteState) string {
+					if s.Mode == "all" {
+						return stepDone
+					}
+					return "mode"
+				},
+			},
+		},
+	}
+}
+
+func (c *ChatDeleterReminder) deleteById(s *deleteState, msg string) error {
+	rmd := r.NewReminder()
+	if err := rmd.SetNumber(msg); err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorParsingId), err)
+	}
+
+	rmd, err := c.db.GetReminderByNumber(context.Background(), s.User.Id, rmd.Number)
+	if err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err)
+	} else if rmd.Id == 0 {
+		return errors.New(domain.ReplyNoSuchId)
+	}
+
+	if _, err := c.db.DeleteReminder(context.Background(), s.User.Id, rmd.Id); isMissing(err) {
+		return errors.New(domain.ReplyNoSuchId)
+	} else if err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorDeletingReminder), err)
+	}
+
+	c.SendMessage(domain.ReplyDone, rmd.UndoKeyboard())
+
+	return nil
+}
+
+func (c *ChatDeleterReminder) deleteByTag(s *deleteState, msg string) error {
+	rmd := r.NewReminder()
+	if err := rmd.SetTag(msg); err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorParsingTag), err)
+	}
+
+	nDeleted, err := c.db.DeleteRemindersByTag(context.Background(), s.User.Id, rmd.Tag)
+	if err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorDeletingReminder), err)
+	} else if nDeleted == 0 {
+		return errors.New(domain.ReplyNoSuchTag)
+	}
+
+	c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyDeletedMultiple), nDeleted), c.undoKeyboard(s.User.Id))
+
+	return nil
+}
+
+func (c *ChatDeleterReminder) deleteAll(s *deleteState, msg string) error {
+	switch strings.ToLower(msg) {
+	case "yes":
+		nDeleted, err := c.db.DeleteRemindersByUserId(context.Background(), s.User.Id)
+		if err != nil {
+			return fmt.Errorf(c.tr(domain.ReplyErrorDeletingReminder), err)
+		} else if nDeleted == 0 {
+			return abort(domain.ReplyNoReminders)
 		}
+
+		c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyDeletedMultiple), nDeleted), c.undoKeyboard(s.User.Id))
+		return nil
+
+	case "no":
+		c.SendMessage(domain.ReplyCancel, nil)
+		s.Mode = "none"
+		return nil
+
+	default:
+		return errors.New(domain.ReplyYesNo)
 	}
 }
+
+// isMissing tells reminder isn't there for the user, someone else's reminder is reported the same way
+func isMissing(err error) bool {
+	return errors.Is(err, domain.ErrorReminderNotFound) || errors.Is(err, domain.ErrorForbidden)
+}
+
+// undoKeyboard restores the latest deleted batch, all reminders deleted by one command share their deletion time
+func (c *Chat) undoKeyboard(userId int) domain.Keyboard {
+	rmds, err := c.db.GetDeletedRemindersByUserId(context.Background(), userId, 1)
+	if err != nil || len(rmds) == 0 {
+		return nil
+	}
+
+	return rmds[0].UndoKeyboard()
+}
@@ -2,18 +2,22 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vedomirr/remindista/internal/domain"
+	u "github.com/vedomirr/remindista/internal/entity/user"
 )
 
 type ChatDeleteReminderById struct {
 	*Chat
-	rmdId int
+	rmdId  int
+	cardId int
 }
 
-func NewChatDeleteReminderById(chat *Chat, rmdId int) *ChatDeleteReminderById {
-	c := &ChatDeleteReminderById{chat, rmdId}
+// NewChatDeleteReminderById asks to confirm deletion, the card with the pressed button is struck through afterwards
+func NewChatDeleteReminderById(chat *Chat, rmdId int, cardId int) *ChatDeleteReminderById {
+	c := &ChatDeleteReminderById{chat, rmdId, cardId}
 
 	go c.chat()
 
@@ -21,37 +25,65 @@ func NewChatDeleteReminderById(chat *Chat, rmdId int) *ChatDeleteReminderById {
 }
 
 func (c *ChatDeleteReminderById) chat() {
-	defer close(c.inCh)
 	defer c.deleteChat()
 
-	c.SendMessage(domain.ReplyeConfirmDelete, domain.KbYesNo)
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	converse(c.Chat, c.flow(), &deleteByIdState{User: user, RmdId: c.rmdId, CardId: c.cardId})
+}
+
+type deleteByIdState struct {
+	User   u.User `json:"-"`
+	RmdId  int    `json:"reminder_id"`
+	CardId int    `json:"card_id"`
+}
 
-	for msg := range c.inCh {
-		if c.isCancel(msg) {
-			c.SendMessage(domain.ReplyCancel, nil)
-			return
-		}
+func (c *ChatDeleteReminderById) flow() flow[deleteByIdState] {
+	return flow[deleteByIdState]{
+		name: flowDeleteReminderById,
+		steps: []step[deleteByIdState]{
+			{
+				name: "confirm",
+				prompt: func(*deleteByIdState) (string, domain.Keyboard) {
+					return domain.ReplyeConfirmDelete, domain.KbYesNo
+				},
+				apply: func(s *deleteByIdState, msg string) error {
+					switch msg {
+					case "yes":
+						rmd, err := c.db.GetReminder(context.Background(), s.User.Id, s.RmdId)
+						if isMissing(err) {
+							return abort(domain.ReplyNoSuchId)
+						} else if err != nil {
+							return fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err)
+						}
 
-		switch msg {
-		case "yes":
-			if nDeleted, err := c.db.DeleteReminder(context.Background(), c.rmdId); err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorDeletingReminder, err).Error(), nil)
-				break
+						if _, err := c.db.DeleteReminder(context.Background(), s.User.Id, s.RmdId); isMissing(err) {
+							return abort(domain.ReplyNoSuchId)
+						} else if err != nil {
+							return fmt.Errorf(c.tr(domain.ReplyErrorDeletingReminder), err)
+						}
 
-			} else if nDeleted == 0 {
-				c.SendMessage(domain.ReplyNoSuchId, nil)
-				break
-			}
+						if s.CardId == 0 {
+							c.SendMessage(domain.ReplyDone, rmd.UndoKeyboard())
+							return nil
+						}
 
-			c.SendMessage(domain.ReplyDone, nil)
-			return
+						c.EditMessage(s.CardId, rmd.DeletedMdV2(c.lang), rmd.UndoKeyboard())
+						c.SendMessage(domain.ReplyDone, nil)
+						return nil
 
-		case "no":
-			c.SendMessage(domain.ReplyCancel, nil)
-			return
+					case "no":
+						return abort(domain.ReplyCancel)
 
-		default:
-			c.SendMessage(domain.ReplyeConfirmDelete, domain.KbYesNo)
-		}
+					default:
+						return errors.New(domain.ReplyeConfirmDelete)
+					}
+				},
+			},
+		},
 	}
 }
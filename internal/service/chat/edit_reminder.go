@@ -0,0 +1,160 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+
+	"go.uber.org/zap"
+)
+
+// editFlows maps edit callbacks to the flows changing a single reminder field
+var editFlows = map[string]string{
+	domain.CallbackEditText:      flowEditText,
+	domain.CallbackEditTag:       flowEditTag,
+	domain.CallbackEditPrompt:    flowEditPrompt,
+	domain.CallbackEditFrequency: flowEditFrequency,
+	domain.CallbackEditWindow:    flowEditWindow,
+}
+
+type ChatEditReminder struct {
+	*Chat
+	rmdId    int
+	callback string
+}
+
+func NewChatEditReminder(chat *Chat, rmdId int, callback string) *ChatEditReminder {
+	c := &ChatEditReminder{Chat: chat, rmdId: rmdId, callback: callback}
+
+	go c.chat()
+
+	return c
+}
+
+func (c *ChatEditReminder) chat() {
+	defer c.deleteChat()
+
+	f, ok := c.flow()
+	if !ok {
+		c.log.Error("unknown edit callback", zap.String("callback", c.callback))
+		return
+	}
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	// resumed conversation restores reminder from its state
+	rmd := r.NewReminder()
+	if c.rmdId != 0 {
+		if rmd, err = c.db.GetReminder(context.Background(), user.Id, c.rmdId); isMissing(err) {
+			c.SendMessage(domain.ReplyNoSuchId, nil)
+			return
+		} else if err != nil {
+			c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error(), nil)
+			return
+		}
+	}
+
+	converse(c.Chat, f, &reminderState{User: user, Rmd: rmd})
+}
+
+func (c *ChatEditReminder) flow() (flow[reminderState], bool) {
+	name, ok := editFlows[c.callback]
+	if !ok {
+		return flow[reminderState]{}, false
+	}
+
+	var edit step[reminderState]
+
+	switch name {
+	case flowEditText:
+		edit = step[reminderState]{
+			prompt: func(s *reminderState) (string, domain.Keyboard) {
+				return fmt.Sprintf(c.tr(domain.ReplyEditReminderText), s.Rmd.TextMdV2()), domain.KbCancel
+			},
+			apply: func(s *reminderState, msg string) error {
+				s.Rmd.Text = msg
+				return nil
+			},
+		}
+
+	case flowEditTag:
+		edit = step[reminderState]{
+			prompt: func(s *reminderState) (string, domain.Keyboard) {
+				tag := s.Rmd.TagMdV2()
+				if tag == "" {
+					tag = c.tr(domain.ReplyNoTag)
+				}
+				return fmt.Sprintf(c.tr(domain.ReplyEditReminderTag), tag), domain.KbEditTag
+			},
+			apply: c.applyTag,
+		}
+
+	case flowEditPrompt:
+		edit = step[reminderState]{
+			prompt: func(s *reminderState) (string, domain.Keyboard) {
+				prompt := s.Rmd.PromptMdV2()
+				if prompt == "" {
+					prompt = c.tr(domain.ReplyNoPromt)
+				}
+				return fmt.Sprintf(c.tr(domain.ReplyEditReminderPrompt), prompt), domain.KbEditPrompt
+			},
+			apply: func(s *reminderState, msg string) error {
+				if msg == "no_prompt" {
+					msg = ""
+				}
+				s.Rmd.Prompt = msg
+				return nil
+			},
+		}
+
+	case flowEditFrequency:
+		edit = step[reminderState]{
+			prompt: func(s *reminderState) (string, domain.Keyboard) {
+				return fmt.Sprintf(c.tr(domain.ReplyEditReminderFrequency), s.Rmd.FreqeuncyString(c.lang)), domain.KbCancel
+			},
+			apply: c.applyFrequency,
+		}
+
+	case flowEditWindow:
+		edit = step[reminderState]{
+			prompt: func(s *reminderState) (string, domain.Keyboard) {
+				window := s.Rmd.WindowMdV2()
+				if window == "" {
+					window = c.tr(domain.ReplyNoWindow)
+				}
+				return fmt.Sprintf(c.tr(domain.ReplyEditReminderWindow), window), domain.KbEditWindow
+			},
+			apply: func(s *reminderState, msg string) error {
+				if err := s.Rmd.SetWindow(msg); err != nil {
+					return fmt.Errorf(c.tr(domain.ReplyErrorParsingWindow), err)
+				}
+
+				s.Rmd.UpdateNextReminder(s.User.Time(), s.User.FloorDuration(), s.User.CeilDuration())
+
+				return nil
+			},
+		}
+	}
+
+	edit.name = "value"
+
+	return flow[reminderState]{
+		name:  name,
+		steps: []step[reminderState]{edit},
+		done: func(s *reminderState) {
+			if _, err := c.db.UpdateReminder(context.Background(), s.Rmd); err != nil {
+				c.log.Error("failed to update reminder", zap.Error(err))
+				c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorUpdatingReminder), err).Error(), nil)
+				return
+			}
+
+			c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyReminderUpdated), s.Rmd.NextReminderString(c.lang)), nil)
+		},
+	}, true
+}
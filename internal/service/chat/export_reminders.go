@@ -0,0 +1,93 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	"github.com/vedomirr/remindista/pkg/anki"
+
+	"go.uber.org/zap"
+)
+
+type ChatExportReminders struct {
+	*Chat
+}
+
+func NewChatExportReminders(chat *Chat) *ChatExportReminders {
+	c := &ChatExportReminders{chat}
+
+	go c.chat()
+
+	return c
+}
+
+func (c *ChatExportReminders) chat() {
+	defer c.deleteChat()
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	rmds, err := c.db.GetRemindersByUserId(context.Background(), user.Id)
+	if err != nil {
+		c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error(), nil)
+		return
+	}
+
+	if len(rmds) == 0 {
+		c.SendMessage(domain.ReplyNoReminders, domain.KbAdd)
+		return
+	}
+
+	converse(c.Chat, c.flow(rmds), &struct{}{})
+}
+
+func (c *ChatExportReminders) flow(rmds []r.Reminder) flow[struct{}] {
+	return flow[struct{}]{
+		name: flowExportReminders,
+		steps: []step[struct{}]{
+			{
+				name: "tag",
+				prompt: func(*struct{}) (string, domain.Keyboard) {
+					return domain.ReplyExportTag, domain.KbExport
+				},
+				apply: func(_ *struct{}, msg string) error {
+					selected, name := rmds, "remindista"
+					if msg != "all" {
+						if selected = rmdsByTag(rmds, msg); len(selected) == 0 {
+							return errors.New(domain.ReplyNoRemindersWithTag)
+						}
+						if deck := tagToDeck(selected[0].Tag); deck != "" {
+							name += "_" + deck
+						}
+					}
+
+					data, err := anki.Export(ankiNotes(selected))
+					if err != nil {
+						c.log.Error("failed to export anki package", zap.Error(err))
+						return abort(fmt.Errorf(c.tr(domain.ReplyErrorExporting), err).Error())
+					}
+
+					c.SendDocument(fmt.Sprintf(c.tr(domain.ReplyExported), len(selected)), domain.Document{Name: name + ".apkg", Data: data})
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+func ankiNotes(rmds []r.Reminder) []anki.Note {
+	notes := make([]anki.Note, 0, len(rmds))
+
+	for _, rmd := range rmds {
+		notes = append(notes, anki.Note{Front: rmd.Text, Back: rmd.Prompt, Deck: tagToDeck(rmd.Tag)})
+	}
+
+	return notes
+}
@@ -0,0 +1,243 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+	"github.com/vedomirr/remindista/pkg/anki"
+	"github.com/vedomirr/remindista/pkg/mdcards"
+
+	"go.uber.org/zap"
+)
+
+var errorUnsupportedFile = errors.New("unsupported file type")
+
+type ChatImportReminders struct {
+	*Chat
+}
+
+type importState struct {
+	User    u.User       `json:"-"`
+	NewRmds []r.Reminder `json:"new"`
+	UpdRmds []r.Reminder `json:"updated"`
+}
+
+func NewChatImportReminders(chat *Chat) *ChatImportReminders {
+	c := &ChatImportReminders{chat}
+
+	go c.chat()
+
+	return c
+}
+
+func (c *ChatImportReminders) chat() {
+	defer c.deleteChat()
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	converse(c.Chat, c.flow(), &importState{User: user})
+}
+
+func (c *ChatImportReminders) flow() flow[importState] {
+	return flow[importState]{
+		name: flowImportReminders,
+		steps: []step[importState]{
+			{
+				name: "file",
+				prompt: func(*importState) (string, domain.Keyboard) {
+					return domain.ReplySendImportFile, domain.KbCancel
+				},
+				document: c.readDocument,
+				next: func(s *importState) string {
+					// nothing new, no need to ask for frequency
+					if len(s.NewRmds) == 0 {
+						return stepDone
+					}
+					return "frequency"
+				},
+			},
+			{
+				name: "frequency",
+				prompt: func(s *importState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplyImportFound), len(s.NewRmds), len(s.UpdRmds)), domain.KbCancel
+				},
+				apply: func(s *importState, msg string) error {
+					freq := r.NewReminder()
+					if err := freq.SetFrequency(msg); err != nil {
+						return fmt.Errorf(c.tr(domain.ReplyErrorParsingFrequency), err)
+					}
+
+					for i := range s.NewRmds {
+						s.NewRmds[i].Frequency = freq.Frequency
+						s.NewRmds[i].UpdateNextReminder(s.User.Time(), s.User.FloorDuration(), s.User.CeilDuration())
+					}
+
+					return nil
+				},
+				document: func(*importState, domain.Document) error {
+					return errors.New(domain.ReplyFileAlreadySent)
+				},
+			},
+		},
+		done: func(s *importState) {
+			c.save(s.User, s.NewRmds, s.UpdRmds)
+		},
+	}
+}
+
+func (c *ChatImportReminders) readDocument(s *importState, doc domain.Document) (err error) {
+	rmds, err := c.parseDocument(doc, s.User.Id)
+	if errors.Is(err, errorUnsupportedFile) {
+		return errors.New(domain.ReplyUnsupportedFile)
+	} else if err != nil {
+		c.log.Error("failed to parse imported file", zap.String("file", doc.Name), zap.Error(err))
+		return fmt.Errorf(c.tr(domain.ReplyErrorImporting), err)
+	}
+
+	if len(rmds) == 0 {
+		return errors.New(domain.ReplyNoCardsFound)
+	}
+
+	if s.NewRmds, s.UpdRmds, err = c.splitExisting(rmds, s.User.Id); err != nil {
+		return abort(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error())
+	}
+
+	return nil
+}
+
+// parseDocument turns an uploaded file into reminders, markdown cards carry a source key
+func (c *ChatImportReminders) parseDocument(doc domain.Document, userId int) ([]r.Reminder, error) {
+	switch ext := strings.ToLower(filepath.Ext(doc.Name)); {
+	case ext == ".apkg":
+		notes, err := anki.Import(doc.Data)
+		if err != nil {
+			return nil, err
+		}
+		return ankiReminders(notes, userId), nil
+
+	case mdcards.IsMarkdown(doc.Name):
+		return markdownReminders(mdcards.Parse(doc.Name, doc.Data), userId), nil
+
+	case ext == ".zip":
+		cards, err := mdcards.ParseVault(doc.Data)
+		if err != nil {
+			return nil, err
+		}
+		return markdownReminders(cards, userId), nil
+
+	default:
+		return nil, errorUnsupportedFile
+	}
+}
+
+// splitExisting separates reminders imported before, matched by source key, from the new ones
+func (c *ChatImportReminders) splitExisting(rmds []r.Reminder, userId int) (newRmds, updRmds []r.Reminder, err error) {
+	for _, rmd := range rmds {
+		if rmd.SourceKey == "" {
+			newRmds = append(newRmds, rmd)
+			continue
+		}
+
+		existing, err := c.db.GetReminderBySourceKey(context.Background(), userId, rmd.SourceKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if existing.Id == 0 {
+			newRmds = append(newRmds, rmd)
+			continue
+		}
+
+		existing.Text, existing.Prompt, existing.Tag = rmd.Text, rmd.Prompt, rmd.Tag
+		updRmds = append(updRmds, existing)
+	}
+
+	return newRmds, updRmds, nil
+}
+
+func (c *ChatImportReminders) save(user u.User, newRmds, updRmds []r.Reminder) {
+	nCreated, nUpdated := 0, 0
+
+	for _, rmd := range newRmds {
+		if _, err := c.db.CreateReminder(context.Background(), rmd); err != nil {
+			c.log.Error("failed to create imported reminder", zap.Int("user_id", user.Id), zap.Error(err))
+			continue
+		}
+		nCreated++
+	}
+
+	for _, rmd := range updRmds {
+		if _, err := c.db.UpdateReminder(context.Background(), rmd); err != nil {
+			c.log.Error("failed to update imported reminder", zap.Int("reminder_id", rmd.Id), zap.Error(err))
+			continue
+		}
+		nUpdated++
+	}
+
+	if nFailed := len(newRmds) + len(updRmds) - nCreated - nUpdated; nFailed > 0 {
+		c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyImportedPartially), nCreated, nUpdated, nFailed), nil)
+		return
+	}
+
+	c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyImported), nCreated, nUpdated), nil)
+}
+
+func markdownReminders(cards []mdcards.Card, userId int) []r.Reminder {
+	rmds := make([]r.Reminder, 0, len(cards))
+
+	for _, card := range cards {
+		rmd := r.NewReminder(r.WithUserId(userId), r.WithSourceKey(card.Key))
+		rmd.Text = card.Front
+		rmd.Prompt = card.Back
+
+		// tag is optional, leave reminder untagged if it can't be a tag
+		_ = rmd.SetTag(card.Tag)
+
+		rmds = append(rmds, rmd)
+	}
+
+	return rmds
+}
+
+func ankiReminders(notes []anki.Note, userId int) []r.Reminder {
+	rmds := make([]r.Reminder, 0, len(notes))
+
+	for _, note := range notes {
+		rmd := r.NewReminder(r.WithUserId(userId))
+		rmd.Text = note.Front
+		rmd.Prompt = note.Back
+
+		// deck is optional, leave reminder untagged if it can't be a tag
+		_ = rmd.SetTag(deckToTag(note.Deck))
+
+		rmds = append(rmds, rmd)
+	}
+
+	return rmds
+}
+
+// deckToTag turns "Languages::English Words" into "languages_english_words", default deck means no tag
+func deckToTag(deck string) string {
+	if deck == "" || deck == "Default" {
+		return ""
+	}
+
+	deck = strings.ReplaceAll(deck, "::", "_")
+
+	return strings.Join(strings.Fields(deck), "_")
+}
+
+// tagToDeck reverses deckToTag as far as possible
+func tagToDeck(tag string) string {
+	return strings.TrimPrefix(tag, "#")
+}
@@ -3,6 +3,7 @@ package chat
 import (
 	"context"
 
+	"github.com/vedomirr/remindista/internal/domain"
 	r "github.com/vedomirr/remindista/internal/entity/reminder"
 	u "github.com/vedomirr/remindista/internal/entity/user"
 )
@@ -10,6 +11,7 @@ import (
 type repository interface {
 	repoUsers
 	repoReminders
+	repoConversations
 }
 
 type repoUsers interface {
@@ -17,15 +19,27 @@ type repoUsers interface {
 	GetUser(ctx context.Context, id int) (user u.User, err error)
 	GetUserByTelegramId(ctx context.Context, telegramId int64) (user u.User, err error)
 	UpdateUser(ctx context.Context, user u.User) (affected int, err error)
+	UpdateUserCalendarToken(ctx context.Context, id int, token string) (affected int, err error)
 	DeleteUser(ctx context.Context, id int, telegramId int64) (affected int, err error)
 }
 
 type repoReminders interface {
 	CreateReminder(ctx context.Context, rmd r.Reminder) (id int, err error)
-	GetReminder(ctx context.Context, id int) (rmd r.Reminder, err error)
+	GetReminder(ctx context.Context, userId int, id int) (rmd r.Reminder, err error)
+	GetReminderByNumber(ctx context.Context, userId int, number int) (rmd r.Reminder, err error)
+	GetReminderBySourceKey(ctx context.Context, userId int, sourceKey string) (rmd r.Reminder, err error)
 	GetRemindersByUserId(ctx context.Context, userId int) (rmds []r.Reminder, err error)
 	UpdateReminder(ctx context.Context, rmd r.Reminder) (affected int, err error)
-	DeleteReminder(ctx context.Context, id int) (affected int, err error)
+	DeleteReminder(ctx context.Context, userId int, id int) (affected int, err error)
 	DeleteRemindersByTag(ctx context.Context, userId int, tag string) (affected int, err error)
 	DeleteRemindersByUserId(ctx context.Context, userId int) (affected int, err error)
+	GetTagCounts(ctx context.Context, userId int) (tags []r.TagCount, err error)
+	RenameTag(ctx context.Context, userId int, from, to string) (affected int, err error)
+	SearchReminders(ctx context.Context, userId int, query string, limit int) (hits []r.SearchHit, err error)
+	GetDeletedRemindersByUserId(ctx context.Context, userId int, limit int) (rmds []r.Reminder, err error)
+}
+
+type repoConversations interface {
+	SaveConversation(ctx context.Context, conv domain.Conversation) error
+	DeleteConversation(ctx context.Context, chatId int64) (affected int, err error)
 }
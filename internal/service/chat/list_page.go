@@ -0,0 +1,120 @@
+package chat
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+)
+
+const (
+	listPageSize    = 10
+	listRowLength   = 40 // runes of reminder text shown in a row
+	listItemsPerRow = 5
+	listNoTag       = "no_tag"
+)
+
+// list sorting orders, position is kept in page callbacks
+var listSorts = []struct {
+	name    string
+	compare func(a, b r.Reminder) int
+}{
+	{"Next due", func(a, b r.Reminder) int { return a.NextReminder.Compare(b.NextReminder) }},
+	{"A–Z", func(a, b r.Reminder) int { return cmp.Compare(strings.ToLower(a.Text), strings.ToLower(b.Text)) }},
+	{"Frequency", func(a, b r.Reminder) int { return cmp.Compare(a.Frequency, b.Frequency) }},
+	{"Newest", func(a, b r.Reminder) int { return cmp.Compare(b.Id, a.Id) }},
+}
+
+// ListQuery picks a page of the reminders list
+type ListQuery struct {
+	Tag  string // reminders tag or `no_tag`, empty lists all reminders
+	Sort int
+	Page int
+}
+
+// ParseListQuery reads the query from page callback, which is `:list_page <page> <sort> [tag]`
+func ParseListQuery(page int, args []string) ListQuery {
+	q := ListQuery{Page: page}
+
+	if len(args) > 0 {
+		q.Sort, _ = strconv.Atoi(args[0])
+	}
+	if len(args) > 1 {
+		q.Tag = args[1]
+	}
+
+	return q
+}
+
+func (q ListQuery) callback(page, sort int) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %d %d %s", domain.CallbackListPage, page, sort, q.Tag))
+}
+
+// ListPage renders a page of the reminders list as a single message in the language,
+// its keyboard opens cards, changes sorting and flips pages by editing the message
+func ListPage(lang string, rmds []r.Reminder, q ListQuery) (string, domain.Keyboard) {
+	title := domain.Tr(lang, domain.ReplyAllReminders)
+	if q.Tag != "" {
+		rmds = rmdsByTag(rmds, q.Tag)
+		title = domain.Tr(lang, domain.ReplyNoTag)
+		if len(rmds) > 0 && rmds[0].Tag != "" {
+			title = rmds[0].TagMdV2()
+		}
+	}
+
+	if len(rmds) == 0 {
+		return domain.Tr(lang, domain.ReplyNoReminders), nil
+	}
+
+	if q.Sort < 0 || q.Sort >= len(listSorts) {
+		q.Sort = 0
+	}
+	sorted := slices.Clone(rmds)
+	slices.SortStableFunc(sorted, listSorts[q.Sort].compare)
+
+	pages := (len(sorted) + listPageSize - 1) / listPageSize
+	q.Page = min(max(q.Page, 0), pages-1)
+
+	first := q.Page * listPageSize
+	page := sorted[first:min(first+listPageSize, len(sorted))]
+
+	var rows strings.Builder
+	items := make([]domain.Item, 0, len(page))
+	for _, rmd := range page {
+		rows.WriteString("\n`" + rmd.NumberString() + "` " + rmd.RowMdV2(lang, listRowLength))
+		items = append(items, domain.Item{Key: rmd.NumberString(), Val: fmt.Sprintf("%s %d", domain.CallbackOpenCard, rmd.Number)})
+	}
+
+	var kb domain.Keyboard
+	for chunk := range slices.Chunk(items, listItemsPerRow) {
+		kb = append(kb, chunk)
+	}
+
+	sorts := make([]domain.Item, 0, len(listSorts))
+	for i, sort := range listSorts {
+		key := domain.Tr(lang, sort.name)
+		if i == q.Sort {
+			key = "• " + key
+		}
+		sorts = append(sorts, domain.Item{Key: key, Val: q.callback(0, i)})
+	}
+	kb = append(kb, sorts)
+
+	if pages > 1 {
+		nav := make([]domain.Item, 0, 3)
+		if q.Page > 0 {
+			nav = append(nav, domain.Item{Key: domain.Tr(lang, "◀ Prev"), Val: q.callback(q.Page-1, q.Sort)})
+		}
+		nav = append(nav, domain.Item{Key: fmt.Sprintf("%d/%d", q.Page+1, pages), Val: domain.CallbackNoop + " 0"})
+		if q.Page < pages-1 {
+			nav = append(nav, domain.Item{Key: domain.Tr(lang, "Next ▶"), Val: q.callback(q.Page+1, q.Sort)})
+		}
+		kb = append(kb, nav)
+	}
+
+	return fmt.Sprintf(domain.Tr(lang, domain.ReplyListPage), title, len(sorted), rows.String()), kb
+}
@@ -0,0 +1,138 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+)
+
+func testReminders(n int) []r.Reminder {
+	now := time.Now()
+	rmds := make([]r.Reminder, 0, n)
+
+	for i := 1; i <= n; i++ {
+		rmd := r.Reminder{
+			Id:           i,
+			Number:       i,
+			Text:         fmt.Sprintf("reminder %02d", i),
+			Frequency:    time.Duration(n-i+1) * time.Hour,
+			NextReminder: now.Add(time.Duration(i%3) * time.Hour),
+			IsActive:     i != 5,
+		}
+		if i%2 == 0 {
+			rmd.Tag = "#even"
+		}
+		rmds = append(rmds, rmd)
+	}
+
+	return rmds
+}
+
+// items returns callbacks of the numbered buttons opening cards
+func items(kb domain.Keyboard) (callbacks []string) {
+	for _, row := range kb {
+		for _, item := range row {
+			if strings.HasPrefix(item.Val, domain.CallbackOpenCard) {
+				callbacks = append(callbacks, item.Val)
+			}
+		}
+	}
+	return callbacks
+}
+
+func TestListPage(t *testing.T) {
+	rmds := testReminders(23)
+
+	t.Run("first page", func(t *testing.T) {
+		text, kb := ListPage("en", rmds, ListQuery{Sort: 1})
+
+		if got := items(kb); len(got) != listPageSize || got[0] != domain.CallbackOpenCard+" 1" {
+			t.Errorf("items = %q, want %d starting with reminder 1", got, listPageSize)
+		}
+		if !strings.Contains(text, "`#1` reminder 01") || strings.Contains(text, "reminder 11") {
+			t.Errorf("text = %q, want reminders 1-10", text)
+		}
+		if !strings.Contains(text, "`#5` ⏸ reminder 05") {
+			t.Errorf("text = %q, want reminder 5 marked as paused", text)
+		}
+
+		nav := kb[len(kb)-1]
+		if len(nav) != 2 || nav[0].Key != "1/3" || nav[1].Val != domain.CallbackListPage+" 1 1" {
+			t.Errorf("navigation = %v, want page indicator and next", nav)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		text, kb := ListPage("en", rmds, ListQuery{Sort: 1, Page: 5})
+
+		if got := items(kb); len(got) != 3 {
+			t.Errorf("items = %q, want 3", got)
+		}
+		if !strings.Contains(text, "`#21` reminder 21") {
+			t.Errorf("text = %q, want numbering to continue", text)
+		}
+
+		nav := kb[len(kb)-1]
+		if len(nav) != 2 || nav[0].Val != domain.CallbackListPage+" 1 1" || nav[1].Key != "3/3" {
+			t.Errorf("navigation = %v, want prev and page indicator", nav)
+		}
+	})
+
+	t.Run("sorting", func(t *testing.T) {
+		_, kb := ListPage("en", rmds, ListQuery{Sort: 3})
+
+		if got := items(kb); got[0] != domain.CallbackOpenCard+" 23" {
+			t.Errorf("newest first = %q, want reminder 23", got[0])
+		}
+
+		_, kb = ListPage("en", rmds, ListQuery{Sort: 2})
+		if got := items(kb); got[0] != domain.CallbackOpenCard+" 23" {
+			t.Errorf("most frequent first = %q, want reminder 23", got[0])
+		}
+	})
+
+	t.Run("tag keeps filter in callbacks", func(t *testing.T) {
+		text, kb := ListPage("en", rmds, ListQuery{Tag: "#even"})
+
+		if got := items(kb); len(got) != 10 {
+			t.Errorf("items = %q, want 10 even reminders", got)
+		}
+		if !strings.HasPrefix(text, "*\\#even* \\(11\\)") {
+			t.Errorf("text = %q, want tag title with count", text)
+		}
+
+		nav := kb[len(kb)-1]
+		if want := domain.CallbackListPage + " 1 0 #even"; nav[len(nav)-1].Val != want {
+			t.Errorf("next = %q, want %q", nav[len(nav)-1].Val, want)
+		}
+	})
+
+	t.Run("single page has no navigation", func(t *testing.T) {
+		_, kb := ListPage("en", rmds[:3], ListQuery{})
+
+		if len(kb) != 2 {
+			t.Errorf("keyboard = %v, want items and sorting rows", kb)
+		}
+	})
+
+	t.Run("nothing left", func(t *testing.T) {
+		text, kb := ListPage("en", rmds, ListQuery{Tag: "#odd"})
+
+		if text != domain.ReplyNoReminders || kb != nil {
+			t.Errorf("ListPage() = %q, %v, want no reminders reply", text, kb)
+		}
+	})
+}
+
+func TestParseListQuery(t *testing.T) {
+	q := ListQuery{Tag: "#go", Sort: 2, Page: 4}
+
+	parts := strings.Split(q.callback(q.Page, q.Sort), " ")
+	if got := ParseListQuery(4, parts[2:]); got != q {
+		t.Errorf("ParseListQuery() = %+v, want %+v", got, q)
+	}
+}
@@ -2,18 +2,24 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/vedomirr/remindista/internal/domain"
 	r "github.com/vedomirr/remindista/internal/entity/reminder"
 )
 
+const tagsPerRow = 3
+
 type ChatListReminders struct {
 	*Chat
+	tag string
 }
 
-func NewChatListReminders(chat *Chat) *ChatListReminders {
-	c := &ChatListReminders{chat}
+// NewChatListReminders lists reminders right away if tag or `all` is given, otherwise asks for it
+func NewChatListReminders(chat *Chat, tag string) *ChatListReminders {
+	c := &ChatListReminders{chat, strings.TrimSpace(tag)}
 
 	go c.chat()
 
@@ -21,7 +27,6 @@ func NewChatListReminders(chat *Chat) *ChatListReminders {
 }
 
 func (c *ChatListReminders) chat() {
-	defer close(c.inCh)
 	defer c.deleteChat()
 
 	user, err := c.getUser()
@@ -32,7 +37,7 @@ func (c *ChatListReminders) chat() {
 
 	rmds, err := c.db.GetRemindersByUserId(context.Background(), user.Id)
 	if err != nil {
-		c.SendMessage(fmt.Errorf(domain.ReplyErrorGettingReminder, err).Error(), nil)
+		c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error(), nil)
 		return
 	}
 
@@ -41,38 +46,84 @@ func (c *ChatListReminders) chat() {
 		return
 	}
 
-	c.SendMessage(domain.ReplyListReminders, domain.KbListReminders)
+	tags, err := c.db.GetTagCounts(context.Background(), user.Id)
+	if err != nil {
+		c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error(), nil)
+		return
+	}
+
+	state := &listState{Rmds: rmds, Tags: tags}
 
-	for msg := range c.inCh {
-		if c.isCancel(msg) {
-			c.SendMessage(domain.ReplyCancel, nil)
+	if c.tag != "" {
+		err := c.list(state, c.tag)
+		if err == nil {
 			return
 		}
+		c.SendMessage(err.Error(), nil)
+	}
 
-		switch msg {
-		case "all":
-			for _, rmd := range rmds {
-				c.SendMessage(rmd.StringMdV2(), rmd.Keyboard())
-			}
-			return
+	converse(c.Chat, c.flow(), state)
+}
 
-		default:
-			rmdsTag := c.rmdsByTag(rmds, msg)
-			if len(rmdsTag) == 0 {
-				c.SendMessage(domain.ReplyNoRemindersWithTag, domain.KbListReminders)
-				break
-			}
+type listState struct {
+	Rmds   []r.Reminder `json:"-"`
+	Tags   []r.TagCount `json:"-"`
+	Listed bool         `json:"listed"`
+	All    bool         `json:"all"`
+}
 
-			for _, rmd := range rmdsTag {
-				c.SendMessage(rmd.StringMdV2(), rmd.Keyboard())
-			}
+func (c *ChatListReminders) flow() flow[listState] {
+	return flow[listState]{
+		name: flowListReminders,
+		steps: []step[listState]{
+			{
+				name: "tag",
+				prompt: func(s *listState) (string, domain.Keyboard) {
+					kb := c.tagsKeyboard(s.Tags, domain.Item{Key: fmt.Sprintf(c.tr("All (%d)"), r.TotalCount(s.Tags)), Val: "all"})
+					if s.Listed {
+						return domain.ReplyListAnotherTag, kb
+					}
+					return domain.ReplyListReminders, kb
+				},
+				apply: c.list,
+				// keep asking for tags until everything is listed
+				next: func(s *listState) string {
+					if s.All {
+						return stepDone
+					}
+					return "tag"
+				},
+			},
+		},
+	}
+}
+
+// list sends the first page of reminders with the tag, or all of them
+func (c *ChatListReminders) list(s *listState, msg string) error {
+	var q ListQuery
+	if s.All = msg == "all"; !s.All {
+		rmds := rmdsByTag(s.Rmds, msg)
+		if len(rmds) == 0 {
+			return errors.New(domain.ReplyNoRemindersWithTag)
+		}
 
-			c.SendMessage(domain.ReplyListAnotherTag, domain.KbListReminders)
+		if q.Tag = rmds[0].Tag; q.Tag == "" {
+			q.Tag = listNoTag
 		}
 	}
+
+	c.SendMessage(ListPage(c.lang, s.Rmds, q))
+	s.Listed = true
+
+	return nil
+}
+
+// tagsKeyboard is the tag overview with reminder counts, extra buttons follow Cancel in the last row
+func (c *Chat) tagsKeyboard(tags []r.TagCount, extra ...domain.Item) domain.Keyboard {
+	return r.TagsKeyboard(c.lang, tags, tagsPerRow, append([]domain.Item{{Key: "Cancel", Val: "cancel"}}, extra...)...)
 }
 
-func (c *ChatListReminders) rmdsByTag(rmds []r.Reminder, tag string) []r.Reminder {
+func rmdsByTag(rmds []r.Reminder, tag string) []r.Reminder {
 	rmdsTag := make([]r.Reminder, 0)
 
 	for _, rmd := range rmds {
@@ -0,0 +1,72 @@
+package chat
+
+import "github.com/vedomirr/remindista/internal/domain"
+
+// flow names, stored with unfinished conversations
+const (
+	flowAddReminder        = "add_reminder"
+	flowAddRemindersBulk   = "add_reminders_bulk"
+	flowAddUser            = "add_user"
+	flowUpdateUser         = "update_user"
+	flowUpdateReminder     = "update_reminder"
+	flowEditText           = "edit_text"
+	flowEditTag            = "edit_tag"
+	flowEditPrompt         = "edit_prompt"
+	flowEditFrequency      = "edit_frequency"
+	flowEditWindow         = "edit_window"
+	flowDeleteReminder     = "delete_reminder"
+	flowDeleteReminderById = "delete_reminder_by_id"
+	flowListReminders      = "list_reminders"
+	flowImportReminders    = "import_reminders"
+	flowExportReminders    = "export_reminders"
+	flowCalendar           = "calendar"
+	flowSearchReminders    = "search_reminders"
+	flowTags               = "tags"
+)
+
+// Resume restarts a conversation saved before shutdown on the step it stopped at.
+// Returns false if the flow is unknown, the chat is over then without a session.
+func Resume(chat *Chat, conv domain.Conversation, calendarUrl string) bool {
+	chat.resume = &conv
+
+	switch conv.Flow {
+	case flowAddReminder:
+		NewChatAddReminder(chat, "")
+	case flowAddRemindersBulk:
+		NewChatAddRemindersBulk(chat, "")
+	case flowAddUser:
+		NewChatAddUser(chat)
+	case flowUpdateUser:
+		NewChatUpdateUser(chat)
+	case flowUpdateReminder:
+		NewChatUpdateReminder(chat)
+	case flowEditText, flowEditTag, flowEditPrompt, flowEditFrequency, flowEditWindow:
+		for callback, name := range editFlows {
+			if name == conv.Flow {
+				NewChatEditReminder(chat, 0, callback) // reminder is restored from the saved state
+			}
+		}
+	case flowDeleteReminder:
+		NewChatDeleteReminder(chat, "")
+	case flowDeleteReminderById:
+		NewChatDeleteReminderById(chat, 0, 0) // reminder and card ids are restored from the saved state
+	case flowListReminders:
+		NewChatListReminders(chat, "")
+	case flowImportReminders:
+		NewChatImportReminders(chat)
+	case flowExportReminders:
+		NewChatExportReminders(chat)
+	case flowCalendar:
+		NewChatCalendar(chat, calendarUrl)
+	case flowTags:
+		NewChatTags(chat)
+	case flowSearchReminders:
+		NewChatSearchReminders(chat, "")
+	default:
+		chat.resume = nil
+		chat.deleteChat() // nobody else would mark it done
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,94 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+)
+
+// searchLimit is the number of best matches shown
+const searchLimit = 20
+
+var errNothingFound = errors.New(domain.ReplyNothingFound)
+
+type ChatSearchReminders struct {
+	*Chat
+	query string
+}
+
+// NewChatSearchReminders searches right away if query is given, otherwise asks for it
+func NewChatSearchReminders(chat *Chat, query string) *ChatSearchReminders {
+	c := &ChatSearchReminders{chat, strings.TrimSpace(query)}
+
+	go c.chat()
+
+	return c
+}
+
+type searchState struct {
+	User   u.User `json:"-"`
+	Missed bool   `json:"missed"`
+}
+
+func (c *ChatSearchReminders) chat() {
+	defer c.deleteChat()
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	state := &searchState{User: user}
+
+	if c.query != "" {
+		err := c.search(state, c.query)
+		if !errors.Is(err, errNothingFound) {
+			if err != nil {
+				c.SendMessage(err.Error(), nil)
+			}
+			return
+		}
+		state.Missed = true
+	}
+
+	converse(c.Chat, c.flow(), state)
+}
+
+func (c *ChatSearchReminders) flow() flow[searchState] {
+	return flow[searchState]{
+		name: flowSearchReminders,
+		steps: []step[searchState]{
+			{
+				name: "query",
+				prompt: func(s *searchState) (string, domain.Keyboard) {
+					if s.Missed {
+						return domain.ReplyNothingFound, domain.KbCancel
+					}
+					return domain.ReplySearchQuery, domain.KbCancel
+				},
+				apply: c.search,
+			},
+		},
+	}
+}
+
+func (c *ChatSearchReminders) search(s *searchState, query string) error {
+	hits, err := c.db.SearchReminders(context.Background(), s.User.Id, query, searchLimit)
+	if err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err)
+	} else if len(hits) == 0 {
+		return errNothingFound
+	}
+
+	c.SendMessage(fmt.Sprintf(c.tr(domain.ReplySearchFound), len(hits)), nil)
+	for _, hit := range hits {
+		c.SendMessage(hit.StringMdV2(c.lang), hit.Keyboard())
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+)
+
+// tag actions, each one is the name of the step performing it
+const (
+	tagRename    = "rename"
+	tagMerge     = "merge"
+	tagFrequency = "frequency"
+	tagWindow    = "window"
+	tagPause     = "pause"
+	tagResume    = "resume"
+)
+
+type ChatTags struct {
+	*Chat
+}
+
+func NewChatTags(chat *Chat) *ChatTags {
+	c := &ChatTags{chat}
+
+	go c.chat()
+
+	return c
+}
+
+type tagsState struct {
+	User   u.User       `json:"-"`
+	Tags   []r.TagCount `json:"-"`
+	Tag    string       `json:"tag"`
+	Action string       `json:"action"`
+}
+
+func (c *ChatTags) chat() {
+	defer c.deleteChat()
+
+	user, err := c.getUser()
+	if err != nil {
+		c.SendMessage(domain.ReplyFailedFindUser, nil)
+		return
+	}
+
+	tags, err := c.db.GetTagCounts(context.Background(), user.Id)
+	if err != nil {
+		c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error(), nil)
+		return
+	}
+
+	if len(tags) == 0 {
+		c.SendMessage(domain.ReplyNoReminders, domain.KbAdd)
+		return
+	}
+
+	converse(c.Chat, c.flow(), &tagsState{User: user, Tags: tags})
+}
+
+func (c *ChatTags) flow() flow[tagsState] {
+	return flow[tagsState]{
+		name: flowTags,
+		steps: []step[tagsState]{
+			{
+				name: "tag",
+				prompt: func(s *tagsState) (string, domain.Keyboard) {
+					return domain.ReplyTags, c.tagsKeyboard(s.Tags)
+				},
+				apply: func(s *tagsState, msg string) error {
+					tag, ok := findTag(s.Tags, msg)
+					if !ok {
+						return errors.New(domain.ReplyNoSuchTag)
+					}
+					s.Tag = tag.Tag
+					return nil
+				},
+			},
+			{
+				name: "action",
+				prompt: func(s *tagsState) (string, domain.Keyboard) {
+					tag, _ := findTag(s.Tags, s.Tag)
+					return fmt.Sprintf(c.tr(domain.ReplyTagActions), tag.TagMdV2(c.lang), tag.Count), domain.KbTagActions
+				},
+				apply: func(s *tagsState, msg string) error {
+					switch msg {
+					case tagRename, tagMerge, tagFrequency, tagWindow:
+						s.Action = msg
+						return nil
+					case tagPause, tagResume:
+						s.Action = msg
+						return c.updateAll(s, domain.ReplyTagPaused, func(rmd *r.Reminder) error {
+							rmd.IsActive = msg == tagResume
+							return nil
+						})
+					default:
+						return errors.New(domain.ReplyUnknown)
+					}
+				},
+				next: func(s *tagsState) string {
+					if s.Action == tagPause || s.Action == tagResume {
+						return stepDone
+					}
+					return s.Action
+				},
+			},
+			{
+				name: tagRename,
+				prompt: func(*tagsState) (string, domain.Keyboard) {
+					return domain.ReplyRenameTag, domain.KbCancel
+				},
+				apply: c.rename,
+				next:  func(*tagsState) string { return stepDone },
+			},
+			{
+				name: tagMerge,
+				prompt: func(s *tagsState) (string, domain.Keyboard) {
+					others := slices.DeleteFunc(slices.Clone(s.Tags), func(t r.TagCount) bool { return t.Tag == s.Tag })
+					return domain.ReplyMergeTag, c.tagsKeyboard(others)
+				},
+				apply: func(s *tagsState, msg string) error {
+					target, ok := findTag(s.Tags, msg)
+					if !ok {
+						return errors.New(domain.ReplyNoSuchTag)
+					}
+					return c.renameTo(s, target.Tag)
+				},
+				next: func(*tagsState) string { return stepDone },
+			},
+			{
+				name: tagFrequency,
+				prompt: func(*tagsState) (string, domain.Keyboard) {
+					return domain.ReplyTagFrequency, domain.KbCancel
+				},
+				apply: func(s *tagsState, msg string) error {
+					return c.updateAll(s, domain.ReplyTagUpdated, func(rmd *r.Reminder) error {
+						if err := rmd.SetFrequency(msg); err != nil {
+							return fmt.Errorf(c.tr(domain.ReplyErrorParsingFrequency), err)
+						}
+						return nil
+					})
+				},
+				next: func(*tagsState) string { return stepDone },
+			},
+			{
+				name: tagWindow,
+				prompt: func(*tagsState) (string, domain.Keyboard) {
+					return domain.ReplyTagWindow, domain.KbEditWindow
+				},
+				apply: func(s *tagsState, msg string) error {
+					return c.updateAll(s, domain.ReplyTagUpdated, func(rmd *r.Reminder) error {
+						if err := rmd.SetWindow(msg); err != nil {
+							return fmt.Errorf(c.tr(domain.ReplyErrorParsingWindow), err)
+						}
+						return nil
+					})
+				},
+				next: func(*tagsState) string { return stepDone },
+			},
+		},
+	}
+}
+
+func (c *ChatTags) rename(s *tagsState, msg string) error {
+	rmd := r.NewReminder()
+	if err := rmd.SetTag(msg); err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorParsingTag), err)
+	}
+
+	if rmd.Tag == s.Tag {
+		return errors.New(domain.ReplySameTag)
+	}
+
+	return c.renameTo(s, rmd.Tag)
+}
+
+// renameTo moves reminders of the picked tag under another one, merging them if it already exists
+func (c *ChatTags) renameTo(s *tagsState, tag string) error {
+	if tag == s.Tag {
+		return errors.New(domain.ReplySameTag)
+	}
+
+	_, merged := findTag(s.Tags, tag)
+
+	affected, err := c.db.RenameTag(context.Background(), s.User.Id, s.Tag, tag)
+	if err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorUpdatingReminder), err)
+	}
+
+	target := r.TagCount{Tag: tag}
+	if merged {
+		c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyTagsMerged), affected, target.TagMdV2(c.lang)), nil)
+	} else {
+		c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyTagRenamed), affected, target.TagMdV2(c.lang)), nil)
+	}
+
+	return nil
+}
+
+// updateAll applies the change to every reminder under the picked tag and reschedules them from now
+func (c *ChatTags) updateAll(s *tagsState, reply string, change func(rmd *r.Reminder) error) error {
+	rmds, err := c.db.GetRemindersByUserId(context.Background(), s.User.Id)
+	if err != nil {
+		return fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err)
+	}
+
+	updated := 0
+	for _, rmd := range rmds {
+		if rmd.Tag != s.Tag {
+			continue
+		}
+
+		// input is the same for every reminder, so the first failure fails them all
+		if err := change(&rmd); err != nil {
+			return err
+		}
+		rmd.UpdateNextReminder(s.User.Time(), s.User.FloorDuration(), s.User.CeilDuration())
+
+		if _, err := c.db.UpdateReminder(context.Background(), rmd); err != nil {
+			return fmt.Errorf(c.tr(domain.ReplyErrorUpdatingReminder), err)
+		}
+		updated++
+	}
+
+	c.SendMessage(fmt.Sprintf(reply, updated), nil)
+
+	return nil
+}
+
+// findTag looks up user's tag by name or `no_tag`, hash sign is optional
+func findTag(tags []r.TagCount, name string) (r.TagCount, bool) {
+	for _, tag := range tags {
+		probe := r.Reminder{Tag: tag.Tag}
+		if probe.TagMatches(name) {
+			return tag, true
+		}
+	}
+	return r.TagCount{}, false
+}
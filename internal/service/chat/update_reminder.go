@@ -2,6 +2,7 @@ package chat
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/vedomirr/remindista/internal/domain"
@@ -23,7 +24,6 @@ func NewChatUpdateReminder(chat *Chat) *ChatUpdateReminder {
 }
 
 func (c *ChatUpdateReminder) chat() {
-	defer close(c.inCh)
 	defer c.deleteChat()
 
 	user, err := c.getUser()
@@ -32,89 +32,89 @@ func (c *ChatUpdateReminder) chat() {
 		return
 	}
 
-	rmd := r.NewReminder()
-
-	stage := "id"
-	c.SendMessage(domain.ReplySendId, domain.KbCancel)
-
-	for msg := range c.inCh {
-		if c.isCancel(msg) {
-			c.SendMessage(domain.ReplyCancel, nil)
-			return
-		}
-
-		switch stage {
-		case "id":
-			if err := rmd.HexToId(msg); err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingId, err).Error(), nil)
-				break
-			}
-
-			if rmd, err = c.db.GetReminder(context.Background(), rmd.Id); err != nil {
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorGettingReminder, err).Error(), nil)
-				return
-
-			} else if rmd.Id == 0 {
-				c.SendMessage(domain.ReplyNoSuchId, nil)
-				break
-			}
-
-			c.SendMessage(fmt.Sprintf(domain.ReplyUpdateReminderText, rmd.TextMdV2()), domain.KbSkip)
-			stage = "text"
-
-		case "text":
-			if !c.skipped(msg) {
-				rmd.Text = msg
+	id := step[reminderState]{
+		name: "id",
+		prompt: func(*reminderState) (string, domain.Keyboard) {
+			return domain.ReplySendId, domain.KbCancel
+		},
+		apply: func(s *reminderState, msg string) (err error) {
+			if err := s.Rmd.SetNumber(msg); err != nil {
+				return fmt.Errorf(c.tr(domain.ReplyErrorParsingId), err)
 			}
 
-			tag := rmd.TagMdV2()
-			if tag == "" {
-				tag = "no tag"
+			if s.Rmd, err = c.db.GetReminderByNumber(context.Background(), s.User.Id, s.Rmd.Number); err != nil {
+				return abort(fmt.Errorf(c.tr(domain.ReplyErrorGettingReminder), err).Error())
+			} else if s.Rmd.Id == 0 {
+				return errors.New(domain.ReplyNoSuchId)
 			}
 
-			c.SendMessage(fmt.Sprintf(domain.ReplyUpdateReminderTag, tag), domain.KbSkip)
-			stage = "tag"
-
-		case "tag":
-			if !c.skipped(msg) {
-				if err := rmd.SetTag(msg); err != nil {
-					c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingTag, err).Error(), domain.KbSkip)
-					break
-				}
-			}
-
-			if rmd.Prompt == "" {
-				rmd.Prompt = domain.ReplyNoPromt
-			}
-
-			c.SendMessage(fmt.Sprintf(domain.ReplyUpdateReminderPrompt, rmd.PromptMdV2()), domain.KbSkip)
-			stage = "prompt"
-
-		case "prompt":
-			if !c.skipped(msg) {
-				rmd.Prompt = msg
-			}
+			return nil
+		},
+	}
 
-			c.SendMessage(fmt.Sprintf(domain.ReplyUpdateReminderFrequency, rmd.FreqeuncyString()), domain.KbSkip)
-			stage = "frequency"
+	f := c.updateReminderFlow(flowUpdateReminder)
+	f.steps = append([]step[reminderState]{id}, f.steps...)
 
-		case "frequency":
-			if !c.skipped(msg) {
-				if err := rmd.SetFrequency(msg); err != nil {
-					c.SendMessage(fmt.Errorf(domain.ReplyErrorParsingFrequency, err).Error(), nil)
-					break
-				}
-				rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
-			}
+	converse(c.Chat, f, &reminderState{User: user, Rmd: r.NewReminder()})
+}
 
-			if _, err := c.db.UpdateReminder(context.Background(), rmd); err != nil {
+// updateReminderFlow walks through every reminder field offering to keep the current value
+func (c *Chat) updateReminderFlow(name string) flow[reminderState] {
+	return flow[reminderState]{
+		name: name,
+		steps: []step[reminderState]{
+			{
+				name: "text",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderText), s.Rmd.TextMdV2()), domain.KbSkip
+				},
+				apply: func(s *reminderState, msg string) error {
+					s.Rmd.Text = msg
+					return nil
+				},
+				skip: skipStep[reminderState],
+			},
+			{
+				name: "tag",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					tag := s.Rmd.TagMdV2()
+					if tag == "" {
+						tag = "no tag"
+					}
+					return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderTag), tag), domain.KbSkip
+				},
+				apply: c.applyTag,
+				skip:  skipStep[reminderState],
+			},
+			{
+				name: "prompt",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					prompt := s.Rmd.PromptMdV2()
+					if prompt == "" {
+						prompt = c.tr(domain.ReplyNoPromt)
+					}
+					return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderPrompt), prompt), domain.KbSkip
+				},
+				apply: c.applyPrompt,
+				skip:  skipStep[reminderState],
+			},
+			{
+				name: "frequency",
+				prompt: func(s *reminderState) (string, domain.Keyboard) {
+					return fmt.Sprintf(c.tr(domain.ReplyUpdateReminderFrequency), s.Rmd.FreqeuncyString(c.lang)), domain.KbSkip
+				},
+				apply: c.applyFrequency,
+				skip:  skipStep[reminderState],
+			},
+		},
+		done: func(s *reminderState) {
+			if _, err := c.db.UpdateReminder(context.Background(), s.Rmd); err != nil {
 				c.log.Error("failed to update reminder", zap.Error(err))
-				c.SendMessage(fmt.Errorf(domain.ReplyErrorUpdatingReminder, err).Error(), nil)
+				c.SendMessage(fmt.Errorf(c.tr(domain.ReplyErrorUpdatingReminder), err).Error(), nil)
 				return
 			}
 
-			c.SendMessage(fmt.Sprintf(domain.ReplyReminderSet, rmd.NextReminderString()), nil)
-			return
-		}
+			c.SendMessage(fmt.Sprintf(c.tr(domain.ReplyReminderSet), s.Rmd.NextReminderString(c.lang)), nil)
+		},
 	}
 }
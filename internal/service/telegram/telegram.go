@@ -2,22 +2,47 @@ package telegram
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/vedomirr/l"
 	"github.com/vedomirr/remindista/internal/domain"
+	"github.com/vedomirr/remindista/pkg/render"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 )
 
+const (
+	maxFileSize     = 20 << 20 // bot api doesn't allow downloading larger files
+	inlineCacheTime = 10       // seconds, results are personal and change often
+)
+
 type Telegram struct {
-	bot *tgbotapi.BotAPI
-	log *zap.Logger
+	bot      *tgbotapi.BotAPI
+	client   *http.Client
+	renderer render.Renderer
+	log      *zap.Logger
+
+	// webhook receives updates instead of long polling when set, see WithWebhook
+	webhook *webhook
 }
 
-func NewTelegram(token string) (t *Telegram, err error) {
-	t = &Telegram{bot: nil, log: l.Logger()}
+type TelegramOption func(*Telegram) error
+
+// NewTelegram sends messages in the markup of the renderer, replies are built in MarkdownV2 and converted to it
+func NewTelegram(token string, renderer render.Renderer, opts ...TelegramOption) (t *Telegram, err error) {
+	t = &Telegram{bot: nil, client: &http.Client{Timeout: time.Minute}, renderer: renderer, log: l.Logger()}
+	for _, opt := range opts {
+		if err := opt(t); err != nil {
+			return nil, err
+		}
+	}
+
 	if t.bot, err = tgbotapi.NewBotAPI(token); err != nil {
 		return nil, err
 	}
@@ -26,28 +51,28 @@ func NewTelegram(token string) (t *Telegram, err error) {
 	return t, nil
 }
 
+// ReceiveMessages streams incoming updates posted to the webhook if there is one, otherwise they're long polled
 func (t *Telegram) ReceiveMessages(ctx context.Context) chan domain.Message {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	var updates <-chan tgbotapi.Update
+	if t.webhook != nil {
+		updates = t.webhook.updates
+	} else {
+		updates = t.receivePolling(ctx)
+	}
 
-	updates := t.bot.GetUpdatesChan(u)
 	messages := make(chan domain.Message)
 
-	go func(updates tgbotapi.UpdatesChannel, messages chan domain.Message, ctx context.Context) {
-		defer t.bot.StopReceivingUpdates()
-
+	go func(updates <-chan tgbotapi.Update, messages chan domain.Message, ctx context.Context) {
 		for {
 			select {
 			case update := <-updates:
 				if update.Message != nil {
 					messages <- mapMessage(update.Message)
 				} else if update.CallbackQuery != nil {
-					callback := tgbotapi.NewCallback(update.CallbackQuery.ID, update.CallbackQuery.Data)
-					if _, err := t.bot.Request(callback); err != nil {
-						t.log.Error("failed to send callback", zap.Error(err))
-					}
-
+					// callback is answered once it's processed, see AnswerCallback
 					messages <- mapCallback(update.CallbackQuery)
+				} else if update.InlineQuery != nil {
+					messages <- mapInlineQuery(update.InlineQuery)
 				}
 
 			case <-ctx.Done():
@@ -60,6 +85,27 @@ func (t *Telegram) ReceiveMessages(ctx context.Context) chan domain.Message {
 	return messages
 }
 
+func (t *Telegram) receivePolling(ctx context.Context) <-chan tgbotapi.Update {
+	// updates can't be polled while a webhook is set, it's not deleted here since it may be serving them
+	if info, err := t.bot.GetWebhookInfo(); err != nil {
+		t.log.Error("failed to get webhook info", zap.Error(err))
+	} else if info.IsSet() {
+		t.log.Error("webhook is set, updates can't be polled until it's deleted", zap.String("url", info.URL))
+	}
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	updates := t.bot.GetUpdatesChan(u)
+
+	go func() {
+		<-ctx.Done()
+		t.bot.StopReceivingUpdates()
+	}()
+
+	return updates
+}
+
 func (t *Telegram) SendMessage(chatID int64, text string, keyboard domain.Keyboard) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 
@@ -74,15 +120,34 @@ func (t *Telegram) SendMessage(chatID int64, text string, keyboard domain.Keyboa
 	return nil
 }
 
-func (t *Telegram) SendMessageMarkdownV2(chatID int64, html string, keyboard domain.Keyboard) error {
-	msg := tgbotapi.NewMessage(chatID, html)
+// SendMessageMarkdownV2 returns id of the sent message, text is sent in the markup of the renderer
+func (t *Telegram) SendMessageMarkdownV2(chatID int64, text string, keyboard domain.Keyboard) (int, error) {
+	msg := tgbotapi.NewMessage(chatID, "")
 
-	msg.ParseMode = tgbotapi.ModeMarkdownV2
+	msg.Text, msg.ParseMode = t.rendered(text)
 
 	if keyboard != nil {
 		msg.ReplyMarkup = inlineKeyboard(keyboard)
 	}
 
+	sent, err := t.bot.Send(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	return sent.MessageID, nil
+}
+
+func (t *Telegram) EditMessageMarkdownV2(chatID int64, messageID int, text string, keyboard domain.Keyboard) error {
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, "")
+
+	msg.Text, msg.ParseMode = t.rendered(text)
+
+	if keyboard != nil {
+		markup := inlineKeyboard(keyboard)
+		msg.ReplyMarkup = &markup
+	}
+
 	if _, err := t.bot.Send(msg); err != nil {
 		return err
 	}
@@ -90,14 +155,19 @@ func (t *Telegram) SendMessageMarkdownV2(chatID int64, html string, keyboard dom
 	return nil
 }
 
-func (t *Telegram) SendMessageHTML(chatID int64, html string, keyboard domain.Keyboard) error {
-	msg := tgbotapi.NewMessage(chatID, html)
+// AnswerCallback stops button's loading animation, non-empty text is shown as a toast
+func (t *Telegram) AnswerCallback(callbackId string, text string) error {
+	if _, err := t.bot.Request(tgbotapi.NewCallback(callbackId, text)); err != nil {
+		return err
+	}
 
-	msg.ParseMode = tgbotapi.ModeHTML
+	return nil
+}
 
-	if keyboard != nil {
-		msg.ReplyMarkup = inlineKeyboard(keyboard)
-	}
+func (t *Telegram) SendDocument(chatID int64, caption string, document domain.Document) error {
+	msg := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: document.Name, Bytes: document.Data})
+
+	msg.Caption, msg.ParseMode = t.rendered(caption)
 
 	if _, err := t.bot.Send(msg); err != nil {
 		return err
@@ -106,24 +176,166 @@ func (t *Telegram) SendMessageHTML(chatID int64, html string, keyboard domain.Ke
 	return nil
 }
 
+func (t *Telegram) AnswerInlineQuery(queryId string, results []domain.InlineResult) error {
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: queryId,
+		Results:       make([]interface{}, 0, len(results)),
+		CacheTime:     inlineCacheTime,
+		IsPersonal:    true,
+	}
+
+	for _, result := range results {
+		text, parseMode := t.rendered(result.Text)
+		article := tgbotapi.NewInlineQueryResultArticle(result.Id, result.Title, text)
+		article.InputMessageContent = tgbotapi.InputTextMessageContent{Text: text, ParseMode: parseMode}
+		article.Description = result.Description
+
+		if result.Keyboard != nil {
+			keyboard := inlineKeyboard(result.Keyboard)
+			article.ReplyMarkup = &keyboard
+		}
+
+		answer.Results = append(answer.Results, article)
+	}
+
+	if _, err := t.bot.Request(answer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// rendered converts MarkdownV2 replies are built in to the markup of the renderer
+func (t *Telegram) rendered(mdV2 string) (text, parseMode string) {
+	if _, ok := t.renderer.(render.MarkdownV2); ok {
+		return mdV2, tgbotapi.ModeMarkdownV2
+	}
+	return t.renderer.Render(render.ParseMarkdownV2(mdV2)), t.renderer.ParseMode()
+}
+
+// RegisterCommands sets the command menu of every scope, default language descriptions go without language code
+func (t *Telegram) RegisterCommands(commands []domain.Command) error {
+	scopes := map[domain.CommandScope]tgbotapi.BotCommandScope{
+		domain.ScopePrivate:    tgbotapi.NewBotCommandScopeAllPrivateChats(),
+		domain.ScopeGroup:      tgbotapi.NewBotCommandScopeAllGroupChats(),
+		domain.ScopeGroupAdmin: tgbotapi.NewBotCommandScopeAllChatAdministrators(),
+	}
+
+	for _, scope := range domain.Scopes {
+		for i, lang := range domain.Languages {
+			botCommands := make([]tgbotapi.BotCommand, 0, len(commands))
+			for _, cmd := range commands {
+				if cmd.Scope&scope != 0 {
+					botCommands = append(botCommands, tgbotapi.BotCommand{Command: strings.TrimPrefix(cmd.Name, "/"), Description: cmd.DescriptionIn(lang)})
+				}
+			}
+
+			code := lang
+			if i == 0 {
+				code = ""
+			}
+
+			if _, err := t.bot.Request(tgbotapi.NewSetMyCommandsWithScopeAndLanguage(scopes[scope], code, botCommands...)); err != nil {
+				return fmt.Errorf("failed to set commands (scope: %d, language: %q): %w", scope, lang, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsChatAdmin tells the user is the chat's creator or administrator
+func (t *Telegram) IsChatAdmin(chatId int64, userId int64) (bool, error) {
+	member, err := t.bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatId, UserID: userId},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat member: %w", err)
+	}
+
+	return member.IsCreator() || member.IsAdministrator(), nil
+}
+
+// BotName is bot's username, groups address commands to it as `/cmd@name`
+func (t *Telegram) BotName() string {
+	return t.bot.Self.UserName
+}
+
+// StartLink is a deep link opening the bot with /start command followed by payload
+func (t *Telegram) StartLink(payload string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", t.bot.Self.UserName, payload)
+}
+
+func (t *Telegram) DownloadFile(fileId string) ([]byte, error) {
+	url, err := t.bot.GetFileDirectURL(fileId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file url: %w", err)
+	}
+
+	resp, err := t.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return data, nil
+}
+
 func mapMessage(m *tgbotapi.Message) domain.Message {
-	return domain.Message{
+	message := domain.Message{
 		ChatId:     m.Chat.ID,
+		ChatType:   m.Chat.Type,
+		ChatTitle:  m.Chat.Title,
 		TelegramId: m.From.ID,
 		UserName:   m.From.UserName,
+		Lang:       m.From.LanguageCode,
 		Text:       m.Text,
 	}
+
+	if m.Document != nil {
+		message.Document = &domain.Document{Id: m.Document.FileID, Name: m.Document.FileName}
+	}
+
+	if m.ReplyToMessage != nil {
+		message.ReplyTo = m.ReplyToMessage.MessageID
+	}
+
+	return message
 }
 
 func mapCallback(c *tgbotapi.CallbackQuery) domain.Message {
 	return domain.Message{
 		ChatId:     c.Message.Chat.ID,
+		ChatType:   c.Message.Chat.Type,
+		ChatTitle:  c.Message.Chat.Title,
+		MessageId:  c.Message.MessageID,
+		CallbackId: c.ID,
 		TelegramId: c.From.ID,
 		UserName:   c.From.UserName,
+		Lang:       c.From.LanguageCode,
 		Text:       c.Data,
 	}
 }
 
+func mapInlineQuery(q *tgbotapi.InlineQuery) domain.Message {
+	return domain.Message{
+		TelegramId: q.From.ID,
+		UserName:   q.From.UserName,
+		Lang:       q.From.LanguageCode,
+		Text:       q.Query,
+		Inline:     &domain.InlineQuery{Id: q.ID},
+	}
+}
+
 func inlineKeyboard(keyboardValues domain.Keyboard) (keyboard tgbotapi.InlineKeyboardMarkup) {
 	for _, row := range keyboardValues {
 		keyboardRow := tgbotapi.NewInlineKeyboardRow()
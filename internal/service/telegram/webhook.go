@@ -0,0 +1,121 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+// webhookSecretHeader carries the secret the webhook was set with, requests without it aren't from telegram
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// bot api allows only these characters in the secret
+var reWebhookSecret = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// updates the bot handles, others aren't sent to the webhook at all
+var webhookUpdates = []string{"message", "callback_query", "inline_query"}
+
+type webhook struct {
+	url    *url.URL
+	secret string
+
+	updates chan tgbotapi.Update
+}
+
+// WithWebhook receives updates telegram posts to the public https url instead of long polling,
+// requests are served by WebhookHandler mounted at the url's path
+func WithWebhook(rawUrl, secret string) TelegramOption {
+	return func(t *Telegram) error {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			return err
+		} else if u.Scheme != "https" || u.Host == "" {
+			return errors.New("webhook url must be absolute https one")
+		}
+
+		if !reWebhookSecret.MatchString(secret) {
+			return errors.New("webhook secret must be 1-256 letters, digits, _ or -")
+		}
+
+		t.webhook = &webhook{url: u, secret: secret, updates: make(chan tgbotapi.Update)}
+		return nil
+	}
+}
+
+// WebhookPath is where WebhookHandler should be mounted, empty when updates are polled
+func (t *Telegram) WebhookPath() string {
+	if t.webhook == nil {
+		return ""
+	}
+	if t.webhook.url.Path == "" {
+		return "/"
+	}
+	return t.webhook.url.Path
+}
+
+// WebhookHandler passes updates posted by telegram to ReceiveMessages.
+// Update is acknowledged once it's taken, telegram retries the ones that weren't
+func (t *Telegram) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t.webhook == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		secret := r.Header.Get(webhookSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(t.webhook.secret)) != 1 {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		update, err := t.bot.HandleUpdate(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case t.webhook.updates <- *update:
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// SetWebhook asks telegram to post updates to the webhook, it's a no-op when updates are polled
+func (t *Telegram) SetWebhook() error {
+	if t.webhook == nil {
+		return nil
+	}
+
+	params := make(tgbotapi.Params)
+	params.AddNonEmpty("url", t.webhook.url.String())
+	params.AddNonEmpty("secret_token", t.webhook.secret)
+	if err := params.AddInterface("allowed_updates", webhookUpdates); err != nil {
+		return err
+	}
+
+	// bot api library predates secret tokens, so the method is called directly
+	if _, err := t.bot.MakeRequest("setWebhook", params); err != nil {
+		return err
+	}
+
+	t.log.Info("set telegram webhook", zap.String("url", t.webhook.url.Redacted()))
+	return nil
+}
+
+// DeleteWebhook stops telegram posting updates to whichever webhook is set, pending ones wait for polling
+func (t *Telegram) DeleteWebhook() error {
+	if _, err := t.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return err
+	}
+
+	t.log.Info("deleted telegram webhook")
+	return nil
+}
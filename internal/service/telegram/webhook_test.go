@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestWithWebhook(t *testing.T) {
+	testCases := []struct {
+		name    string
+		url     string
+		secret  string
+		wantErr bool
+	}{
+		{"valid", "https://bot.example.com/telegram/updates", "s3cret_token-1", false},
+		{"plain http", "http://bot.example.com/telegram/updates", "s3cret", true},
+		{"relative url", "/telegram/updates", "s3cret", true},
+		{"empty secret", "https://bot.example.com/telegram/updates", "", true},
+		{"secret with spaces", "https://bot.example.com/telegram/updates", "not a token", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := WithWebhook(tc.url, tc.secret)(&Telegram{}); (err != nil) != tc.wantErr {
+				t.Errorf("WithWebhook() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTelegram_WebhookHandler(t *testing.T) {
+	tg := &Telegram{}
+	if err := WithWebhook("https://bot.example.com/telegram/updates", "s3cret")(tg); err != nil {
+		t.Fatal(err)
+	}
+
+	if path := tg.WebhookPath(); path != "/telegram/updates" {
+		t.Errorf("WebhookPath() = %q, want /telegram/updates", path)
+	}
+
+	testCases := []struct {
+		name       string
+		secret     string
+		body       string
+		wantStatus int
+	}{
+		{"no secret", "", `{"update_id": 1}`, http.StatusForbidden},
+		{"wrong secret", "s3cre", `{"update_id": 1}`, http.StatusForbidden},
+		{"malformed update", "s3cret", `{"update_id":`, http.StatusBadRequest},
+		{"update", "s3cret", `{"update_id": 1, "message": {"message_id": 7, "text": "/help", "chat": {"id": 42, "type": "private"}}}`, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/telegram/updates", strings.NewReader(tc.body))
+			if tc.secret != "" {
+				req.Header.Set(webhookSecretHeader, tc.secret)
+			}
+
+			// updates are taken by ReceiveMessages in the app
+			received := make(chan tgbotapi.Update, 1)
+			if tc.wantStatus == http.StatusOK {
+				go func() { received <- <-tg.webhook.updates }()
+			}
+
+			rec := httptest.NewRecorder()
+			tg.WebhookHandler().ServeHTTP(rec, req)
+
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+
+			if tc.wantStatus == http.StatusOK {
+				if update := <-received; update.Message == nil || update.Message.Text != "/help" {
+					t.Errorf("received update %+v, want the posted message", update)
+				}
+			}
+		})
+	}
+}
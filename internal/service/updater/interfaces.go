@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/vedomirr/remindista/internal/domain"
+	g "github.com/vedomirr/remindista/internal/entity/group"
 	r "github.com/vedomirr/remindista/internal/entity/reminder"
 	u "github.com/vedomirr/remindista/internal/entity/user"
 )
@@ -11,16 +12,31 @@ import (
 type telegramService interface {
 	ReceiveMessages(ctx context.Context) chan domain.Message
 	SendMessage(chatId int64, text string, keyboard domain.Keyboard) error
-	SendMessageMarkdownV2(chatId int64, text string, keyboard domain.Keyboard) error
+	SendMessageMarkdownV2(chatId int64, text string, keyboard domain.Keyboard) (messageId int, err error)
+	AnswerCallback(callbackId string, text string) error
+	EditMessageMarkdownV2(chatId int64, messageId int, text string, keyboard domain.Keyboard) error
+	SendDocument(chatId int64, caption string, document domain.Document) error
+	DownloadFile(fileId string) ([]byte, error)
+	AnswerInlineQuery(queryId string, results []domain.InlineResult) error
+	StartLink(payload string) string
+	BotName() string
+	IsChatAdmin(chatId int64, userId int64) (bool, error)
+	RegisterCommands(commands []domain.Command) error
 }
 
 type chattable interface {
 	PassInput(string)
 }
 
+type documentReceiver interface {
+	PassDocument(domain.Document)
+}
+
 type repository interface {
 	repoUsers
 	repoReminders
+	repoGroups
+	repoConversations
 }
 
 type repoUsers interface {
@@ -28,15 +44,41 @@ type repoUsers interface {
 	GetUser(ctx context.Context, id int) (user u.User, err error)
 	GetUserByTelegramId(ctx context.Context, telegramId int64) (user u.User, err error)
 	UpdateUser(ctx context.Context, user u.User) (affected int, err error)
+	UpdateUserCalendarToken(ctx context.Context, id int, token string) (affected int, err error)
 	DeleteUser(ctx context.Context, id int, telegramId int64) (affected int, err error)
 }
 
 type repoReminders interface {
 	CreateReminder(ctx context.Context, rmd r.Reminder) (id int, err error)
-	GetReminder(ctx context.Context, id int) (rmd r.Reminder, err error)
+	GetReminder(ctx context.Context, userId int, id int) (rmd r.Reminder, err error)
+	GetSharedReminder(ctx context.Context, id int) (rmd r.Reminder, err error)
+	GetReminderByNumber(ctx context.Context, userId int, number int) (rmd r.Reminder, err error)
+	GetReminderIdByNumber(ctx context.Context, userId int, number int) (id int, err error)
+	GetReminderByDelivery(ctx context.Context, userId int, chatId int64, messageId int) (rmd r.Reminder, err error)
+	GetReminderBySourceKey(ctx context.Context, userId int, sourceKey string) (rmd r.Reminder, err error)
 	GetRemindersByUserId(ctx context.Context, userId int) (rmds []r.Reminder, err error)
 	UpdateReminder(ctx context.Context, rmd r.Reminder) (affected int, err error)
-	DeleteReminder(ctx context.Context, id int) (affected int, err error)
+	DeleteReminder(ctx context.Context, userId int, id int) (affected int, err error)
 	DeleteRemindersByTag(ctx context.Context, userId int, tag string) (affected int, err error)
 	DeleteRemindersByUserId(ctx context.Context, userId int) (affected int, err error)
+	GetTagCounts(ctx context.Context, userId int) (tags []r.TagCount, err error)
+	RenameTag(ctx context.Context, userId int, from, to string) (affected int, err error)
+	SearchReminders(ctx context.Context, userId int, query string, limit int) (hits []r.SearchHit, err error)
+	GetDeletedRemindersByUserId(ctx context.Context, userId int, limit int) (rmds []r.Reminder, err error)
+	RestoreReminder(ctx context.Context, userId int, id int) (affected int, err error)
+	RestoreReminders(ctx context.Context, userId int, id int) (affected int, err error)
+	PurgeReminder(ctx context.Context, userId int, id int) (affected int, err error)
+}
+
+type repoGroups interface {
+	SaveGroup(ctx context.Context, group g.Group) error
+	GetGroupReminders(ctx context.Context, chatId int64) (rmds []r.Reminder, err error)
+	GetGroupReminderByNumber(ctx context.Context, chatId int64, number int) (rmd r.Reminder, err error)
+	DeleteGroupReminder(ctx context.Context, chatId int64, id int) (affected int, err error)
+}
+
+type repoConversations interface {
+	SaveConversation(ctx context.Context, conv domain.Conversation) error
+	GetConversations(ctx context.Context) (convs []domain.Conversation, err error)
+	DeleteConversation(ctx context.Context, chatId int64) (affected int, err error)
 }
@@ -2,111 +2,249 @@ package updater
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
 	"github.com/vedomirr/remindista/internal/service/chat"
 
 	"go.uber.org/zap"
 )
 
+// processCallback handles the button and answers the callback, short confirmations are shown as a toast
 func (u *Updater) processCallback(m domain.Message) {
-	callback, rmdId, err := u.parseCallbackParams(m.Text)
+	var toast string
+	defer func() {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, CallbackId: m.CallbackId, Text: toast}
+	}()
+
+	callback, param, args, err := u.parseCallbackParams(m.Text)
 	if err != nil {
 		u.log.Error("failed to parse callback params", zap.Error(err))
 		return
 	}
 
-	baseChat := chat.NewChat(m.ChatId, m.TelegramId, u.outCh, u.deleteChatCh, u.db)
+	switch callback {
+	case domain.CallbackListPage:
+		u.listPage(m, chat.ParseListQuery(param, args))
+		return
+
+	case domain.CallbackNoop:
+		// page indicator button, nothing to do but answer
+		return
+	}
+
+	// other callbacks carry the caller's reminder number, every reminder access below is scoped by the caller
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
+	if err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return
+	} else if user.TelegramId == 0 {
+		toast = domain.ToastNoSuchReminder
+		return
+	}
+
+	rmdId, err := u.db.GetReminderIdByNumber(context.Background(), user.Id, param)
+	if err != nil {
+		u.log.Error("failed to resolve reminder number", zap.Int("number", param), zap.Error(err))
+		return
+	} else if rmdId == 0 {
+		toast = domain.ToastNoSuchReminder
+		return
+	}
+
 	switch callback {
 	case domain.CallbackDelete:
-		ct := chat.NewChatDeleteReminderById(baseChat, rmdId)
-		u.chats.Store(m.ChatId, ct)
+		chat.NewChatDeleteReminderById(u.newChat(m), rmdId, m.MessageId)
 
 	case domain.CallbackUpdate:
-		ct := chat.NewChatUpdateReminderById(baseChat, rmdId)
-		u.chats.Store(m.ChatId, ct)
+		u.editMenu(m, user, rmdId)
+
+	case domain.CallbackEditText, domain.CallbackEditTag, domain.CallbackEditPrompt, domain.CallbackEditFrequency, domain.CallbackEditWindow:
+		chat.NewChatEditReminder(u.newChat(m), rmdId, callback)
+
+	case domain.CallbackPause:
+		toast = u.pauseReminder(m, user, rmdId)
+
+	case domain.CallbackResume:
+		toast = u.resumeReminder(m, user, rmdId)
 
 	case domain.CallbackIncreaseFrequency:
-		u.increaseFrequency(m.TelegramId, m.ChatId, rmdId)
+		toast = u.increaseFrequency(m, user, rmdId)
 
 	case domain.CallbackDecreaseFrequency:
-		u.decreaseFrequency(m.TelegramId, m.ChatId, rmdId)
+		toast = u.decreaseFrequency(m, user, rmdId)
+
+	case domain.CallbackOpenCard:
+		u.openCard(m, user, rmdId)
+
+	case domain.CallbackUndoDelete:
+		toast = u.undoDelete(m, user, rmdId)
+
+	case domain.CallbackRestore:
+		toast = u.restoreReminder(m, user, rmdId)
+
+	case domain.CallbackPurge:
+		toast = u.purgeReminder(m, user, rmdId)
 
 	default:
 		u.log.Error("unknown callback", zap.String("callback", callback))
 	}
 }
 
-func (u *Updater) parseCallbackParams(s string) (string, int, error) {
+// parseCallbackParams splits `:callback <number> [args...]`
+func (u *Updater) parseCallbackParams(s string) (string, int, []string, error) {
 	parts := strings.Split(s, " ")
-	if len(parts) != 2 {
-		return "", 0, domain.ErrorInvalidCallback
+	if len(parts) < 2 {
+		return "", 0, nil, domain.ErrorInvalidCallback
 	}
 
 	rmdId, err := strconv.Atoi(parts[1])
 	if err != nil {
-		return "", 0, fmt.Errorf("%w: %w", domain.ErrorInvalidCallback, err)
+		return "", 0, nil, fmt.Errorf("%w: %w", domain.ErrorInvalidCallback, err)
+	}
+
+	return parts[0], rmdId, parts[2:], nil
+}
+
+// reminderError turns failed reminder access into a toast, someone else's reminder looks like a missing one
+func (u *Updater) reminderError(err error, userId int, rmdId int) string {
+	switch {
+	case errors.Is(err, domain.ErrorForbidden):
+		u.log.Warn("access to another user's reminder", zap.Int("user_id", userId), zap.Int("reminder_id", rmdId))
+		return domain.ToastNoSuchReminder
+
+	case errors.Is(err, domain.ErrorReminderNotFound):
+		return domain.ToastNoSuchReminder
+
+	default:
+		u.log.Error("failed to access reminder", zap.Int("user_id", userId), zap.Int("reminder_id", rmdId), zap.Error(err))
+		return ""
 	}
+}
 
-	return parts[0], rmdId, nil
+// editCard replaces the card the button was pressed on with reminder's current state
+func (u *Updater) editCard(m domain.Message, rmd r.Reminder) {
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, MessageId: m.MessageId, Text: rmd.StringMdV2(m.Lang), Keyboard: rmd.Keyboard()}
 }
 
-func (u *Updater) increaseFrequency(tgId, chatId int64, rmdId int) {
-	user, err := u.db.GetUserByTelegramId(context.Background(), tgId)
+// listPage flips the reminders list page or sorting by editing the list message
+func (u *Updater) listPage(m domain.Message, q chat.ListQuery) {
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
 	if err != nil {
-		u.log.Error("failed to get user", zap.Int64("telegram_id", tgId), zap.Error(err))
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
 		return
 	}
 
-	rmd, err := u.db.GetReminder(context.Background(), rmdId)
+	rmds, err := u.db.GetRemindersByUserId(context.Background(), user.Id)
 	if err != nil {
-		u.log.Error("failed to get reminder", zap.Int("reminder_id", rmdId), zap.Error(err))
+		u.log.Error("failed to get reminders", zap.Int("user_id", user.Id), zap.Error(err))
 		return
 	}
 
-	if rmd.Frequency <= time.Minute {
-		u.outCh <- domain.Message{ChatId: chatId, Text: domain.ReplyMinimumFrequency}
+	text, kb := chat.ListPage(m.Lang, rmds, q)
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, MessageId: m.MessageId, Text: text, Keyboard: kb}
+}
+
+func (u *Updater) openCard(m domain.Message, user u.User, rmdId int) {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
+	if err != nil {
+		if u.reminderError(err, user.Id, rmdId) == domain.ToastNoSuchReminder {
+			u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyNoSuchId}
+		}
 		return
 	}
 
-	rmd.Frequency = max(rmd.Frequency/2, time.Minute)
-	rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: rmd.StringMdV2(m.Lang), Keyboard: rmd.Keyboard()}
+}
+
+func (u *Updater) editMenu(m domain.Message, user u.User, rmdId int) {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
+	if err != nil {
+		if u.reminderError(err, user.Id, rmdId) == domain.ToastNoSuchReminder {
+			u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyNoSuchId}
+		}
+		return
+	}
+
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyEditReminder), rmd.StringMdV2(m.Lang)), Keyboard: rmd.EditKeyboard()}
+}
+
+func (u *Updater) pauseReminder(m domain.Message, user u.User, rmdId int) string {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
+	if err != nil {
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	rmd.IsActive = false
 
 	if _, err := u.db.UpdateReminder(context.Background(), rmd); err != nil {
-		u.log.Error("failed to update reminder", zap.Int("reminder_id", rmdId), zap.Error(err))
+		return u.reminderError(err, user.Id, rmdId)
 	}
 
-	u.outCh <- domain.Message{ChatId: chatId, Text: fmt.Sprintf(domain.ReplyFrequencyUpdated, rmd.FreqeuncyString())}
+	u.editCard(m, rmd)
+	return domain.ToastPaused
 }
 
-func (u *Updater) decreaseFrequency(tgId, chatId int64, rmdId int) {
-	user, err := u.db.GetUserByTelegramId(context.Background(), tgId)
+func (u *Updater) resumeReminder(m domain.Message, user u.User, rmdId int) string {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
 	if err != nil {
-		u.log.Error("failed to get user", zap.Int64("telegram_id", tgId), zap.Error(err))
-		return
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	rmd.Resume(user.Time(), user.FloorDuration(), user.CeilDuration())
+
+	if _, err := u.db.UpdateReminder(context.Background(), rmd); err != nil {
+		return u.reminderError(err, user.Id, rmdId)
 	}
 
-	rmd, err := u.db.GetReminder(context.Background(), rmdId)
+	u.editCard(m, rmd)
+	return fmt.Sprintf(domain.Tr(m.Lang, domain.ToastResumed), rmd.NextReminderString(m.Lang))
+}
+
+func (u *Updater) increaseFrequency(m domain.Message, user u.User, rmdId int) string {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
 	if err != nil {
-		u.log.Error("failed to get reminder", zap.Int("reminder_id", rmdId), zap.Error(err))
-		return
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	if rmd.Frequency <= time.Minute {
+		return domain.ToastMinimumFrequency
+	}
+
+	rmd.Frequency = max(rmd.Frequency/2, time.Minute)
+	rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+
+	if _, err := u.db.UpdateReminder(context.Background(), rmd); err != nil {
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	u.editCard(m, rmd)
+	return fmt.Sprintf(domain.Tr(m.Lang, domain.ToastFrequencyUpdated), rmd.FreqeuncyString(m.Lang))
+}
+
+func (u *Updater) decreaseFrequency(m domain.Message, user u.User, rmdId int) string {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
+	if err != nil {
+		return u.reminderError(err, user.Id, rmdId)
 	}
 
 	if rmd.Frequency >= time.Hour*24*365 {
-		u.outCh <- domain.Message{ChatId: chatId, Text: domain.ReplyMaximumFrequency}
-		return
+		return domain.ToastMaximumFrequency
 	}
 
 	rmd.Frequency = min(rmd.Frequency*2, time.Hour*24*365)
 	rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
 
 	if _, err := u.db.UpdateReminder(context.Background(), rmd); err != nil {
-		u.log.Error("failed to update reminder", zap.Int("reminder_id", rmdId), zap.Error(err))
+		return u.reminderError(err, user.Id, rmdId)
 	}
 
-	u.outCh <- domain.Message{ChatId: chatId, Text: fmt.Sprintf(domain.ReplyFrequencyUpdated, rmd.FreqeuncyString())}
+	u.editCard(m, rmd)
+	return fmt.Sprintf(domain.Tr(m.Lang, domain.ToastFrequencyUpdated), rmd.FreqeuncyString(m.Lang))
 }
@@ -0,0 +1,106 @@
+package updater
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+
+	"go.uber.org/zap"
+)
+
+const (
+	callerId   = 1
+	strangerId = 2
+)
+
+// fakeRepo keeps reminders by id and scopes access by owner like the real repository
+type fakeRepo struct {
+	repository
+
+	rmds    map[int]r.Reminder
+	numbers map[int]int // caller's number to reminder id, may point to a stranger's reminder as if forged
+	updated []r.Reminder
+}
+
+func (f *fakeRepo) GetUserByTelegramId(context.Context, int64) (u.User, error) {
+	return u.User{Id: callerId, TelegramId: 10}, nil
+}
+
+func (f *fakeRepo) GetReminderIdByNumber(_ context.Context, _ int, number int) (int, error) {
+	return f.numbers[number], nil
+}
+
+func (f *fakeRepo) GetReminder(_ context.Context, userId int, id int) (r.Reminder, error) {
+	rmd, ok := f.rmds[id]
+	switch {
+	case !ok:
+		return r.Reminder{}, domain.ErrorReminderNotFound
+	case rmd.UserId != userId:
+		return r.Reminder{}, domain.ErrorForbidden
+	}
+	return rmd, nil
+}
+
+func (f *fakeRepo) UpdateReminder(_ context.Context, rmd r.Reminder) (int, error) {
+	if f.rmds[rmd.Id].UserId != rmd.UserId {
+		return 0, domain.ErrorForbidden
+	}
+	f.updated = append(f.updated, rmd)
+	return 1, nil
+}
+
+func newTestUpdater(db repository) *Updater {
+	return &Updater{db: db, outCh: make(chan domain.Message, 10), log: zap.NewNop()}
+}
+
+func TestUpdater_processCallback_Ownership(t *testing.T) {
+	testCases := []struct {
+		name        string
+		callback    string
+		wantToast   string
+		wantUpdated bool
+	}{
+		{"own reminder", domain.CallbackPause + " 1", domain.ToastPaused, true},
+		{"stranger's reminder", domain.CallbackPause + " 2", domain.ToastNoSuchReminder, false},
+		{"stranger's frequency", domain.CallbackIncreaseFrequency + " 2", domain.ToastNoSuchReminder, false},
+		{"unknown number", domain.CallbackPause + " 3", domain.ToastNoSuchReminder, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &fakeRepo{
+				rmds: map[int]r.Reminder{
+					10: {Id: 10, UserId: callerId, Number: 1, Frequency: time.Hour, IsActive: true},
+					20: {Id: 20, UserId: strangerId, Number: 1, Frequency: time.Hour, IsActive: true},
+				},
+				numbers: map[int]int{1: 10, 2: 20},
+			}
+			upd := newTestUpdater(db)
+
+			upd.processCallback(domain.Message{ChatId: 1, MessageId: 5, CallbackId: "cb", TelegramId: 10, Text: tc.callback})
+
+			var answer domain.Message
+			for len(upd.outCh) > 0 {
+				if msg := <-upd.outCh; msg.CallbackId != "" {
+					answer = msg
+				}
+			}
+
+			if answer.Text != tc.wantToast {
+				t.Errorf("toast = %q, want %q", answer.Text, tc.wantToast)
+			}
+			if updated := len(db.updated) > 0; updated != tc.wantUpdated {
+				t.Errorf("updated = %v, want %v", updated, tc.wantUpdated)
+			}
+			for _, rmd := range db.updated {
+				if rmd.UserId != callerId {
+					t.Errorf("updated reminder of user %d", rmd.UserId)
+				}
+			}
+		})
+	}
+}
@@ -5,60 +5,111 @@ import (
 	"fmt"
 
 	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
 	"github.com/vedomirr/remindista/internal/service/chat"
 
 	"go.uber.org/zap"
 )
 
-func (u *Updater) processCmd(m domain.Message) {
-	baseChat := chat.NewChat(m.ChatId, m.TelegramId, u.outCh, u.deleteChatCh, u.db)
-
-	switch m.Text {
+// processCmd runs the command from its arguments when they are given, commands without them start a chat
+func (u *Updater) processCmd(m domain.Message, cmd, args string) {
+	switch cmd {
 	case domain.CmdStart:
+		// deep link payload
+		if args != "" {
+			u.saveShared(m, args)
+			break
+		}
+
 		// check if user exists
 		if ok, err := u.userExists(m.TelegramId); err != nil {
 			u.log.Error("failed to chek user existance", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
 			break
 			// if user not found, create a new one
 		} else if !ok {
-			u.outCh <- domain.Message{ChatId: m.ChatId, Text: fmt.Sprintf(domain.ReplyCreateNewUser, m.UserName)}
-			ct := chat.NewChatAddUser(baseChat)
-			u.chats.Store(m.ChatId, ct)
+			u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyCreateNewUser), m.UserName)}
+			chat.NewChatAddUser(u.newChat(m))
 			break
 		}
 		// in case user exists, greet him
-		u.outCh <- domain.Message{ChatId: m.ChatId, Text: fmt.Sprintf(domain.ReplyStart, m.UserName)}
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyStart), m.UserName)}
 		u.deleteChat(m.ChatId) // delete any existing chats, just in case
 
 	case domain.CmdHelp:
-		u.outCh <- domain.Message{ChatId: m.ChatId, Text: domain.ReplyHelp}
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.Help(m.Lang)}
 		u.deleteChat(m.ChatId) // delete any existing chats, just in case
 
 	case domain.CmdUpdateUser:
-		ct := chat.NewChatUpdateUser(baseChat)
-		u.chats.Store(m.ChatId, ct)
+		chat.NewChatUpdateUser(u.newChat(m))
 
 	case domain.CmdAdd:
-		ct := chat.NewChatAddReminder(baseChat)
-		u.chats.Store(m.ChatId, ct)
+		chat.NewChatAddReminder(u.newChat(m), args)
+
+	case domain.CmdAddBulk:
+		chat.NewChatAddRemindersBulk(u.newChat(m), args)
 
 	case domain.CmdList:
-		ct := chat.NewChatListReminders(baseChat)
-		u.chats.Store(m.ChatId, ct)
+		chat.NewChatListReminders(u.newChat(m), args)
+
+	case domain.CmdTags:
+		chat.NewChatTags(u.newChat(m))
+
+	case domain.CmdSearch:
+		chat.NewChatSearchReminders(u.newChat(m), args)
 
 	case domain.CmdDelete:
-		ct := chat.NewChatDeleteReminder(baseChat)
-		u.chats.Store(m.ChatId, ct)
+		chat.NewChatDeleteReminder(u.newChat(m), args)
 
 	case domain.CmdUpdate:
-		ct := chat.NewChatUpdateReminder(baseChat)
-		u.chats.Store(m.ChatId, ct)
+		// `/update #12` opens the edit menu of the reminder
+		if rmd := r.NewReminder(); rmd.SetNumber(args) == nil {
+			u.deleteChat(m.ChatId)
+			u.updateByNumber(m, rmd.Number)
+			break
+		}
+		chat.NewChatUpdateReminder(u.newChat(m))
+
+	case domain.CmdCalendar:
+		chat.NewChatCalendar(u.newChat(m), u.calendarUrl)
+
+	case domain.CmdImport:
+		chat.NewChatImportReminders(u.newChat(m))
+
+	case domain.CmdExport:
+		chat.NewChatExportReminders(u.newChat(m))
+
+	case domain.CmdTrash:
+		u.trash(m)
+		u.deleteChat(m.ChatId) // delete any existing chats, just in case
 
 	default:
-		u.outCh <- domain.Message{UserName: "Remindista", ChatId: m.ChatId, Text: domain.ReplyUnkonwCommand}
+		u.outCh <- domain.Message{UserName: "Remindista", ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyUnkonwCommand}
+		u.deleteChat(m.ChatId) // previous session doesn't expect a command as input
 	}
 }
 
+func (u *Updater) updateByNumber(m domain.Message, number int) {
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
+	if err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return
+	} else if user.TelegramId == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyFailedFindUser}
+		return
+	}
+
+	rmdId, err := u.db.GetReminderIdByNumber(context.Background(), user.Id, number)
+	if err != nil {
+		u.log.Error("failed to resolve reminder number", zap.Int("number", number), zap.Error(err))
+		return
+	} else if rmdId == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyNoSuchId}
+		return
+	}
+
+	u.editMenu(m, user, rmdId)
+}
+
 func (u *Updater) userExists(tgId int64) (bool, error) {
 	user, err := u.db.GetUserByTelegramId(context.Background(), tgId)
 	if err != nil {
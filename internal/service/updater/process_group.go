@@ -0,0 +1,173 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	g "github.com/vedomirr/remindista/internal/entity/group"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+	"github.com/vedomirr/remindista/internal/service/chat"
+
+	"go.uber.org/zap"
+)
+
+// processGroup handles group chats, there are no chat sessions there so commands run from their arguments
+func (u *Updater) processGroup(m domain.Message) error {
+	if cmd, bot, args, ok := u.parseCmd(m.Text); ok {
+		if bot == "" || strings.EqualFold(bot, u.telegram.BotName()) {
+			u.processGroupCmd(m, cmd, args)
+		}
+		return nil
+	}
+
+	if m.CallbackId != "" {
+		u.processGroupCallback(m)
+	}
+
+	return nil
+}
+
+func (u *Updater) processGroupCmd(m domain.Message, cmd, args string) {
+	switch cmd {
+	case domain.CmdHelp:
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.GroupHelp(m.Lang)}
+
+	case domain.CmdList:
+		u.groupList(m, chat.ListQuery{Tag: args})
+
+	case domain.CmdAdd:
+		if user, ok := u.groupAdmin(m); ok {
+			u.groupAdd(m, user, args)
+		}
+
+	case domain.CmdDelete:
+		if _, ok := u.groupAdmin(m); ok {
+			u.groupDelete(m, args)
+		}
+
+	default:
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyGroupPrivateOnly}
+	}
+}
+
+// processGroupCallback flips group list pages and opens cards, other buttons belong to private chats
+func (u *Updater) processGroupCallback(m domain.Message) {
+	var toast string
+	defer func() {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, CallbackId: m.CallbackId, Text: toast}
+	}()
+
+	callback, param, args, err := u.parseCallbackParams(m.Text)
+	if err != nil {
+		return
+	}
+
+	switch callback {
+	case domain.CallbackListPage:
+		u.groupList(m, chat.ParseListQuery(param, args))
+
+	case domain.CallbackNoop:
+
+	case domain.CallbackOpenCard:
+		rmd, err := u.db.GetGroupReminderByNumber(context.Background(), m.ChatId, param)
+		if err != nil {
+			u.log.Error("failed to get group reminder", zap.Int64("chat_id", m.ChatId), zap.Int("number", param), zap.Error(err))
+			return
+		} else if rmd.Id == 0 {
+			toast = domain.ToastNoSuchReminder
+			return
+		}
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: rmd.StringMdV2(m.Lang)}
+
+	default:
+		toast = domain.ToastPrivateOnly
+	}
+}
+
+// groupAdmin checks the sender may manage group reminders, they need a profile for reminders to follow its schedule
+func (u *Updater) groupAdmin(m domain.Message) (user u.User, ok bool) {
+	isAdmin, err := u.telegram.IsChatAdmin(m.ChatId, m.TelegramId)
+	if err != nil {
+		u.log.Error("failed to check chat admin", zap.Int64("chat_id", m.ChatId), zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return user, false
+	} else if !isAdmin {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyGroupAdminsOnly}
+		return user, false
+	}
+
+	if user, err = u.db.GetUserByTelegramId(context.Background(), m.TelegramId); err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return user, false
+	} else if user.TelegramId == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyGroupNeedsProfile}
+		return user, false
+	}
+
+	return user, true
+}
+
+// groupList sends the list of group reminders, or edits it when a page button is pressed
+func (u *Updater) groupList(m domain.Message, q chat.ListQuery) {
+	rmds, err := u.db.GetGroupReminders(context.Background(), m.ChatId)
+	if err != nil {
+		u.log.Error("failed to get group reminders", zap.Int64("chat_id", m.ChatId), zap.Error(err))
+		return
+	}
+
+	text, kb := chat.ListPage(m.Lang, rmds, q)
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, MessageId: m.MessageId, Text: text, Keyboard: kb}
+}
+
+// groupAdd creates group reminder from a quick add line, frequency can't be asked for later in a group
+func (u *Updater) groupAdd(m domain.Message, user u.User, line string) {
+	rmd := r.NewReminder(r.WithUserId(user.Id), r.WithGroupChatId(m.ChatId))
+	if err := rmd.ParseQuickAdd(line); err != nil || rmd.Frequency == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyGroupAddUsage}
+		return
+	}
+
+	if err := u.db.SaveGroup(context.Background(), g.NewGroup(m.ChatId, m.ChatTitle)); err != nil {
+		u.log.Error("failed to save group", zap.Int64("chat_id", m.ChatId), zap.Error(err))
+		return
+	}
+
+	rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+
+	if _, err := u.db.CreateReminder(context.Background(), rmd); err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Errorf(domain.Tr(m.Lang, domain.ReplyErrorCreatingReminder), err).Error()}
+		return
+	}
+
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyGroupReminderSet), rmd.NextReminderString(m.Lang))}
+}
+
+func (u *Updater) groupDelete(m domain.Message, target string) {
+	rmd := r.NewReminder()
+	if err := rmd.SetNumber(target); err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyGroupDeleteUsage}
+		return
+	}
+
+	rmd, err := u.db.GetGroupReminderByNumber(context.Background(), m.ChatId, rmd.Number)
+	if err != nil {
+		u.log.Error("failed to get group reminder", zap.Int64("chat_id", m.ChatId), zap.Error(err))
+		return
+	} else if rmd.Id == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyNoSuchId}
+		return
+	}
+
+	if _, err := u.db.DeleteGroupReminder(context.Background(), m.ChatId, rmd.Id); errors.Is(err, domain.ErrorReminderNotFound) {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyNoSuchId}
+		return
+	} else if err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Errorf(domain.Tr(m.Lang, domain.ReplyErrorDeletingReminder), err).Error()}
+		return
+	}
+
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyDone}
+}
@@ -0,0 +1,87 @@
+package updater
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	g "github.com/vedomirr/remindista/internal/entity/group"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+)
+
+const groupChatId = -100
+
+// fakeTelegram tells only admins listed are group admins
+type fakeTelegram struct {
+	telegramService
+
+	admins map[int64]bool
+}
+
+func (f *fakeTelegram) IsChatAdmin(_ int64, userId int64) (bool, error) {
+	return f.admins[userId], nil
+}
+
+func (f *fakeTelegram) BotName() string {
+	return "remindista_bot"
+}
+
+type fakeGroupRepo struct {
+	fakeRepo
+
+	created []r.Reminder
+}
+
+func (f *fakeGroupRepo) SaveGroup(context.Context, g.Group) error {
+	return nil
+}
+
+func (f *fakeGroupRepo) CreateReminder(_ context.Context, rmd r.Reminder) (int, error) {
+	f.created = append(f.created, rmd)
+	return len(f.created), nil
+}
+
+func TestUpdater_processGroup_AdminsOnly(t *testing.T) {
+	testCases := []struct {
+		name        string
+		admin       bool
+		text        string
+		wantReply   string
+		wantCreated bool
+	}{
+		{"admin adds", true, "/add Standup notes every 1 day #team", "", true},
+		{"admin adds addressing the bot", true, "/add@remindista_bot Standup notes every 1 day", "", true},
+		{"member can't add", false, "/add Standup notes every 1 day", domain.ReplyGroupAdminsOnly, false},
+		{"member can't delete", false, "/delete #1", domain.ReplyGroupAdminsOnly, false},
+		{"frequency is required", true, "/add Standup notes", domain.ReplyGroupAddUsage, false},
+		{"private commands", true, "/update_user", domain.ReplyGroupPrivateOnly, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &fakeGroupRepo{}
+			upd := newTestUpdater(db)
+			upd.telegram = &fakeTelegram{admins: map[int64]bool{10: tc.admin}}
+
+			m := domain.Message{ChatId: groupChatId, ChatType: "supergroup", TelegramId: 10, Text: tc.text}
+			if err := upd.ProcessMessage(m); err != nil {
+				t.Fatal(err)
+			}
+
+			if created := len(db.created) > 0; created != tc.wantCreated {
+				t.Fatalf("created = %v, want %v", created, tc.wantCreated)
+			}
+			for _, rmd := range db.created {
+				if rmd.GroupChatId != groupChatId || rmd.UserId != callerId {
+					t.Errorf("created reminder for group %d of user %d", rmd.GroupChatId, rmd.UserId)
+				}
+			}
+
+			if tc.wantReply != "" {
+				if reply := <-upd.outCh; reply.Text != tc.wantReply || reply.ChatId != groupChatId {
+					t.Errorf("reply = %q to %d, want %q", reply.Text, reply.ChatId, tc.wantReply)
+				}
+			}
+		})
+	}
+}
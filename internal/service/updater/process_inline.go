@@ -0,0 +1,160 @@
+package updater
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	"github.com/vedomirr/remindista/internal/service/chat"
+
+	"go.uber.org/zap"
+)
+
+const (
+	inlineResultsLimit = 20 // bot api allows up to 50 results per answer
+	inlineTitleLength  = 64
+)
+
+var reSavePayload = regexp.MustCompile(`^save_([0-9]+)_([0-9a-f]{16})$`)
+
+// processInline answers `@bot query` with the caller's reminders matching the query
+func (u *Updater) processInline(m domain.Message) error {
+	results := make([]domain.InlineResult, 0)
+
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if user.TelegramId != 0 {
+		rmds, err := u.db.GetRemindersByUserId(context.Background(), user.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get reminders: %w", err)
+		}
+
+		for _, rmd := range rmds {
+			if len(results) == inlineResultsLimit {
+				break
+			}
+
+			if rmd.Matches(m.Text) {
+				results = append(results, u.inlineResult(m.Lang, rmd))
+			}
+		}
+	}
+
+	if err := u.telegram.AnswerInlineQuery(m.Inline.Id, results); err != nil {
+		return fmt.Errorf("failed to answer inline query: %w", err)
+	}
+
+	return nil
+}
+
+// inlineResult is shared in the language of the sender
+func (u *Updater) inlineResult(lang string, rmd r.Reminder) domain.InlineResult {
+	description := rmd.FreqeuncyString(lang)
+	if rmd.Tag != "" {
+		description = rmd.Tag + " · " + description
+	}
+
+	return domain.InlineResult{
+		Id:          strconv.Itoa(rmd.Id),
+		Title:       rmd.Title(inlineTitleLength),
+		Description: description,
+		Text:        rmd.StringMdV2(lang),
+		Keyboard: domain.TrKeyboard(lang, domain.Keyboard{{
+			{Key: "Save to my reminders", Val: u.telegram.StartLink(u.savePayload(rmd.Id))},
+		}}),
+	}
+}
+
+// savePayload is the deep link payload copying a shared reminder, signed so ids can't be guessed
+func (u *Updater) savePayload(rmdId int) string {
+	return fmt.Sprintf("save_%d_%s", rmdId, u.shareSignature(rmdId))
+}
+
+func (u *Updater) shareSignature(rmdId int) string {
+	mac := hmac.New(sha256.New, []byte(u.shareSecret))
+	mac.Write([]byte(strconv.Itoa(rmdId)))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// parseSavePayload returns id of the shared reminder if the payload is valid
+func (u *Updater) parseSavePayload(payload string) (int, bool) {
+	matches := reSavePayload.FindStringSubmatch(payload)
+	if matches == nil {
+		return 0, false
+	}
+
+	rmdId, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+
+	if !hmac.Equal([]byte(matches[2]), []byte(u.shareSignature(rmdId))) {
+		return 0, false
+	}
+
+	return rmdId, true
+}
+
+// saveShared copies a reminder shared via inline mode to the recipient's reminders
+func (u *Updater) saveShared(m domain.Message, payload string) {
+	rmdId, ok := u.parseSavePayload(payload)
+	if !ok {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplySharedNotFound}
+		return
+	}
+
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
+	if err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return
+	}
+
+	if user.TelegramId == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyCreateNewUser), m.UserName) + "\n" + domain.Tr(m.Lang, domain.ReplySaveAfterSetup)}
+		chat.NewChatAddUser(u.newChat(m))
+		return
+	}
+
+	shared, err := u.db.GetSharedReminder(context.Background(), rmdId)
+	if err != nil {
+		u.log.Error("failed to get reminder", zap.Int("reminder_id", rmdId), zap.Error(err))
+		return
+	}
+
+	if shared.Id == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplySharedNotFound}
+		return
+	}
+
+	rmd := r.NewReminder(r.WithUserId(user.Id))
+	rmd.Text, rmd.Tag, rmd.Prompt, rmd.Frequency = shared.Text, shared.Tag, shared.Prompt, shared.Frequency
+	rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+
+	id, err := u.db.CreateReminder(context.Background(), rmd)
+	if err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Errorf(domain.Tr(m.Lang, domain.ReplyErrorCreatingReminder), err).Error()}
+		return
+	}
+
+	// the copy is read back for the number it got, buttons refer to reminders by it
+	if rmd, err = u.db.GetReminder(context.Background(), user.Id, id); err != nil {
+		u.log.Error("failed to get reminder", zap.Int("reminder_id", id), zap.Error(err))
+		return
+	}
+
+	u.outCh <- domain.Message{
+		ChatId:   m.ChatId,
+		Lang:     m.Lang,
+		Text:     fmt.Sprintf(domain.Tr(m.Lang, domain.ReplySharedSaved), rmd.StringMdV2(m.Lang), rmd.NextReminderString(m.Lang)),
+		Keyboard: rmd.Keyboard(),
+	}
+}
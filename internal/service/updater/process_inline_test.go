@@ -0,0 +1,49 @@
+package updater
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+)
+
+// fakeShareRepo shares stranger's reminder 20, copies get the caller's next number like the real repository gives
+type fakeShareRepo struct {
+	fakeRepo
+}
+
+func (f *fakeShareRepo) GetSharedReminder(_ context.Context, id int) (r.Reminder, error) {
+	return f.rmds[id], nil
+}
+
+func (f *fakeShareRepo) CreateReminder(_ context.Context, rmd r.Reminder) (int, error) {
+	rmd.Id, rmd.Number = 30, 7
+	f.rmds[rmd.Id] = rmd
+	return rmd.Id, nil
+}
+
+func TestUpdater_saveShared(t *testing.T) {
+	db := &fakeShareRepo{fakeRepo{
+		rmds: map[int]r.Reminder{20: {Id: 20, UserId: strangerId, Number: 1, Text: "Goroutines", Frequency: time.Hour, IsActive: true}},
+	}}
+	upd := newTestUpdater(db)
+	upd.shareSecret = "secret"
+
+	upd.saveShared(domain.Message{ChatId: 1, TelegramId: 10}, upd.savePayload(20))
+
+	reply := <-upd.outCh
+	if len(reply.Keyboard) == 0 {
+		t.Fatalf("saved copy has no buttons: %q", reply.Text)
+	}
+
+	for _, row := range reply.Keyboard {
+		for _, button := range row {
+			if !strings.HasSuffix(button.Val, " 7") {
+				t.Errorf("button %q callback = %q, want the copy's number 7", button.Key, button.Val)
+			}
+		}
+	}
+}
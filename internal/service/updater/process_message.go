@@ -2,14 +2,33 @@ package updater
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/vedomirr/remindista/internal/domain"
 )
 
 func (u *Updater) ProcessMessage(m domain.Message) error {
-	if u.isValidCmd(m.Text) {
-		u.processCmd(m)
+	m.Lang = u.language(m.TelegramId, m.Lang)
+
+	if m.Document != nil {
+		return u.processDocument(m)
+	}
+
+	if m.Inline != nil {
+		return u.processInline(m)
+	}
+
+	if m.IsGroup() {
+		return u.processGroup(m)
+	}
+
+	if cmd, bot, args, ok := u.parseCmd(m.Text); ok {
+		// groups address commands as `/cmd@botname`, other bots' commands are not ours to handle
+		if bot == "" || strings.EqualFold(bot, u.telegram.BotName()) {
+			u.processCmd(m, cmd, args)
+		}
 		return nil
 	}
 
@@ -18,6 +37,15 @@ func (u *Updater) ProcessMessage(m domain.Message) error {
 		return nil
 	}
 
+	// buttons of chat prompts are plain input
+	if m.CallbackId != "" {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, CallbackId: m.CallbackId}
+	}
+
+	if m.ReplyTo != 0 && u.replyEdit(m) {
+		return nil
+	}
+
 	if chat, ok := u.chats.Load(m.ChatId); ok {
 		switch v := chat.(type) {
 		case chattable:
@@ -30,12 +58,41 @@ func (u *Updater) ProcessMessage(m domain.Message) error {
 	return nil
 }
 
-func (u *Updater) isValidCmd(s string) bool {
-	re := regexp.MustCompile(`^\/[a-z_]+$`)
-	return re.MatchString(s)
+func (u *Updater) processDocument(m domain.Message) error {
+	chat, ok := u.chats.Load(m.ChatId)
+	if !ok {
+		return nil
+	}
+
+	receiver, ok := chat.(documentReceiver)
+	if !ok {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyUnsupportedFile}
+		return nil
+	}
+
+	data, err := u.telegram.DownloadFile(m.Document.Id)
+	if err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyUnsupportedFile}
+		return fmt.Errorf("failed to download document: %w", err)
+	}
+
+	receiver.PassDocument(domain.Document{Id: m.Document.Id, Name: m.Document.Name, Data: data})
+
+	return nil
+}
+
+// parseCmd splits `/cmd@botname args` into command, bot it's addressed to and the arguments
+func (u *Updater) parseCmd(s string) (cmd, bot, args string, ok bool) {
+	re := regexp.MustCompile(`(?s)^(\/[a-z_]+)(?:@([A-Za-z0-9_]+))?(?:\s+(.*))?$`)
+	matches := re.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return "", "", "", false
+	}
+
+	return matches[1], matches[2], strings.TrimSpace(matches[3]), true
 }
 
 func (u *Updater) isValidCallback(s string) bool {
-	re := regexp.MustCompile(`^:[a-z_]+ [0-9]+$`)
+	re := regexp.MustCompile(`^:[a-z_]+ [0-9]+( [^ ]+)*$`)
 	return re.MatchString(s)
 }
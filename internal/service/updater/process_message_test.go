@@ -0,0 +1,32 @@
+package updater
+
+import "testing"
+
+func TestUpdater_parseCmd(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		wantCmd  string
+		wantBot  string
+		wantArgs string
+		wantOk   bool
+	}{
+		{"plain", "/list", "/list", "", "", true},
+		{"arguments", "/add Learn window functions every 3 days #sql", "/add", "", "Learn window functions every 3 days #sql", true},
+		{"bot name", "/list@remindista_bot #sql", "/list", "remindista_bot", "#sql", true},
+		{"multi-line", "/add_bulk\nfirst\nsecond", "/add_bulk", "", "first\nsecond", true},
+		{"deep link", "/start save_12_abc", "/start", "", "save_12_abc", true},
+		{"not a command", "list #sql", "", "", "", false},
+		{"glued arguments", "/list#sql", "", "", "", false},
+	}
+
+	var u Updater
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, bot, args, ok := u.parseCmd(tc.input)
+			if cmd != tc.wantCmd || bot != tc.wantBot || args != tc.wantArgs || ok != tc.wantOk {
+				t.Errorf("parseCmd(%q) = (%q, %q, %q, %v), want (%q, %q, %q, %v)", tc.input, cmd, bot, args, ok, tc.wantCmd, tc.wantBot, tc.wantArgs, tc.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// replyEdit updates the reminder delivered as the message user replied to,
+// false means the message isn't a delivery of user's reminder and is handled as usual
+func (u *Updater) replyEdit(m domain.Message) bool {
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
+	if err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return false
+	} else if user.TelegramId == 0 {
+		return false
+	}
+
+	rmd, err := u.db.GetReminderByDelivery(context.Background(), user.Id, m.ChatId, m.ReplyTo)
+	if err != nil {
+		u.log.Error("failed to get reminder by delivery", zap.Int64("chat_id", m.ChatId), zap.Int("message_id", m.ReplyTo), zap.Error(err))
+		return false
+	} else if rmd.Id == 0 {
+		return false
+	}
+
+	frequency := rmd.Frequency
+	if err := rmd.ApplyReply(m.Text); err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Errorf(domain.Tr(m.Lang, domain.ReplyErrorReplyEdit), err).Error()}
+		return true
+	}
+
+	if rmd.Frequency != frequency {
+		rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
+	}
+
+	if _, err := u.db.UpdateReminder(context.Background(), rmd); err != nil {
+		u.log.Error("failed to update reminder", zap.Int("reminder_id", rmd.Id), zap.Error(err))
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Errorf(domain.Tr(m.Lang, domain.ReplyErrorUpdatingReminder), err).Error()}
+		return true
+	}
+
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyReminderReplyEdited), rmd.StringMdV2(m.Lang)), Keyboard: rmd.Keyboard()}
+	return true
+}
@@ -0,0 +1,55 @@
+package updater
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+)
+
+// fakeReplyRepo finds caller's reminder 10 delivered as any message
+type fakeReplyRepo struct {
+	fakeRepo
+}
+
+func (f *fakeReplyRepo) GetReminderByDelivery(_ context.Context, userId int, _ int64, _ int) (r.Reminder, error) {
+	return f.GetReminder(context.Background(), userId, 10)
+}
+
+func TestUpdater_replyEdit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		text          string
+		wantFrequency time.Duration
+		wantUpdated   bool
+	}{
+		{"frequency", "every 2 days", 48 * time.Hour, true},
+		{"zero frequency", "every 0 days", 0, false},
+		{"unknown unit", "every 2 weeks", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := &fakeReplyRepo{fakeRepo{
+				rmds: map[int]r.Reminder{10: {Id: 10, UserId: callerId, Number: 1, Text: "Goroutines", Frequency: time.Hour, IsActive: true}},
+			}}
+			upd := newTestUpdater(db)
+
+			if !upd.replyEdit(domain.Message{ChatId: 1, TelegramId: 10, ReplyTo: 5, Text: tc.text}) {
+				t.Fatal("reply to a delivery isn't handled")
+			}
+
+			if updated := len(db.updated) > 0; updated != tc.wantUpdated {
+				t.Fatalf("updated = %v, want %v", updated, tc.wantUpdated)
+			}
+			if tc.wantUpdated && db.updated[0].Frequency != tc.wantFrequency {
+				t.Errorf("frequency = %v, want %v", db.updated[0].Frequency, tc.wantFrequency)
+			}
+			if reply := <-upd.outCh; reply.ChatId != 1 {
+				t.Errorf("reply sent to %d", reply.ChatId)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	u "github.com/vedomirr/remindista/internal/entity/user"
+
+	"go.uber.org/zap"
+)
+
+// trashLimit is the number of recently deleted reminders shown by /trash
+const trashLimit = 20
+
+func (u *Updater) trash(m domain.Message) {
+	user, err := u.db.GetUserByTelegramId(context.Background(), m.TelegramId)
+	if err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", m.TelegramId), zap.Error(err))
+		return
+	}
+
+	if user.TelegramId == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyFailedFindUser}
+		return
+	}
+
+	rmds, err := u.db.GetDeletedRemindersByUserId(context.Background(), user.Id, trashLimit)
+	if err != nil {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Errorf(domain.Tr(m.Lang, domain.ReplyErrorGettingReminder), err).Error()}
+		return
+	}
+
+	if len(rmds) == 0 {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: domain.ReplyTrashEmpty}
+		return
+	}
+
+	days := max(int(u.retention.Hours()/24), 1)
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyTrash), days)}
+
+	for _, rmd := range rmds {
+		rmd.DeletedAt = rmd.DeletedAt.In(user.Location)
+		u.outCh <- domain.Message{
+			ChatId:   m.ChatId,
+			Lang:     m.Lang,
+			Text:     fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyTrashItem), rmd.StringMdV2(m.Lang), rmd.DeletedAtString(m.Lang)),
+			Keyboard: rmd.TrashKeyboard(),
+		}
+	}
+}
+
+// undoDelete restores everything deleted together with the reminder, a single reminder gets its card back
+func (u *Updater) undoDelete(m domain.Message, user u.User, rmdId int) string {
+	restored, err := u.db.RestoreReminders(context.Background(), user.Id, rmdId)
+	if errors.Is(err, domain.ErrorReminderNotFound) {
+		return domain.ToastNotInTrash
+	} else if err != nil {
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	return u.restored(m, user, rmdId, restored)
+}
+
+func (u *Updater) restoreReminder(m domain.Message, user u.User, rmdId int) string {
+	restored, err := u.db.RestoreReminder(context.Background(), user.Id, rmdId)
+	if errors.Is(err, domain.ErrorReminderNotFound) {
+		return domain.ToastNotInTrash
+	} else if err != nil {
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	return u.restored(m, user, rmdId, restored)
+}
+
+func (u *Updater) purgeReminder(m domain.Message, user u.User, rmdId int) string {
+	if _, err := u.db.PurgeReminder(context.Background(), user.Id, rmdId); errors.Is(err, domain.ErrorReminderNotFound) {
+		return domain.ToastNotInTrash
+	} else if err != nil {
+		return u.reminderError(err, user.Id, rmdId)
+	}
+
+	u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, MessageId: m.MessageId, Text: domain.ReplyPurged}
+	return domain.ToastPurged
+}
+
+// restored edits the message the button was on: one reminder is shown as its card, a batch as a summary
+func (u *Updater) restored(m domain.Message, user u.User, rmdId int, restored int) string {
+	rmd, err := u.db.GetReminder(context.Background(), user.Id, rmdId)
+	if err != nil {
+		u.log.Error("failed to get reminder", zap.Int("reminder_id", rmdId), zap.Error(err))
+	}
+
+	if restored == 1 && rmd.Id != 0 {
+		u.editCard(m, rmd)
+	} else {
+		u.outCh <- domain.Message{ChatId: m.ChatId, Lang: m.Lang, MessageId: m.MessageId, Text: fmt.Sprintf(domain.Tr(m.Lang, domain.ReplyRestored), restored)}
+	}
+
+	return fmt.Sprintf(domain.Tr(m.Lang, domain.ToastRestored), restored)
+}
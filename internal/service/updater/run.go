@@ -17,6 +17,7 @@ func (u *Updater) Run(ctx context.Context) {
 
 	u.outCh = make(chan domain.Message)
 	defer close(u.outCh)
+	defer u.closeChats()
 
 	// send out messages that are ready
 	go func(outgoing chan domain.Message) {
@@ -25,6 +26,14 @@ func (u *Updater) Run(ctx context.Context) {
 		}
 	}(u.outCh)
 
+	// command menu follows the registry on every start
+	if err := u.telegram.RegisterCommands(domain.Commands); err != nil {
+		u.log.Error("failed to register commands", zap.Error(err))
+	}
+
+	// continue conversations interrupted by restart
+	u.resumeChats(ctx)
+
 	// process incoming messages in goroutines
 	limit := make(chan struct{}, 100)
 	for {
@@ -34,6 +43,10 @@ func (u *Updater) Run(ctx context.Context) {
 			go u.processIncoming(msg, limit)
 
 		case <-ctx.Done():
+			// messages being processed may still start sessions or reply, sessions are closed after them
+			for range cap(limit) {
+				limit <- struct{}{}
+			}
 			u.log.Info("stopped telegram updater")
 			return
 		}
@@ -43,7 +56,32 @@ func (u *Updater) Run(ctx context.Context) {
 func (u *Updater) Stop() {}
 
 func (u *Updater) sendOut(message domain.Message) {
-	if err := u.telegram.SendMessageMarkdownV2(message.ChatId, message.Text, message.Keyboard); err != nil {
+	// replies sent as they are get translated here, formatted ones are translated before formatting
+	message.Text = domain.Tr(message.Lang, message.Text)
+	message.Keyboard = domain.TrKeyboard(message.Lang, message.Keyboard)
+
+	if message.Document != nil {
+		if err := u.telegram.SendDocument(message.ChatId, message.Text, *message.Document); err != nil {
+			u.log.Error(fmt.Sprintf("failed to send document [%s] %s (id: %v, chatId: %v)", message.UserName, message.Document.Name, message.TelegramId, message.ChatId), zap.Error(err))
+		}
+		return
+	}
+
+	if message.CallbackId != "" {
+		if err := u.telegram.AnswerCallback(message.CallbackId, message.Text); err != nil {
+			u.log.Error(fmt.Sprintf("failed to answer callback [%s] %s (id: %v, chatId: %v)", message.UserName, message.Text, message.TelegramId, message.ChatId), zap.Error(err))
+		}
+		return
+	}
+
+	if message.MessageId != 0 {
+		if err := u.telegram.EditMessageMarkdownV2(message.ChatId, message.MessageId, message.Text, message.Keyboard); err != nil {
+			u.log.Error(fmt.Sprintf("failed to edit message [%s] %s (id: %v, chatId: %v)", message.UserName, message.Text, message.TelegramId, message.ChatId), zap.Error(err))
+		}
+		return
+	}
+
+	if _, err := u.telegram.SendMessageMarkdownV2(message.ChatId, message.Text, message.Keyboard); err != nil {
 		u.log.Error(fmt.Sprintf("failed to send message [%s] %s (id: %v, chatId: %v)", message.UserName, message.Text, message.TelegramId, message.ChatId), zap.Error(err))
 		return
 	}
@@ -1,29 +1,51 @@
 package updater
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/vedomirr/l"
 	"github.com/vedomirr/remindista/internal/domain"
+	"github.com/vedomirr/remindista/internal/service/chat"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
+var activeChatsCount = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: "remindista",
+	Name:      "active_chat_sessions",
+	Help:      "Number of multi-step chat sessions waiting for user input",
+})
+
+func init() {
+	prometheus.MustRegister(activeChatsCount)
+}
+
 type Updater struct {
 	telegram     telegramService
 	db           repository
 	chats        *sync.Map
 	outCh        chan domain.Message
-	deleteChatCh chan int64
+	deleteChatCh chan *chat.Chat
+	calendarUrl  string
+	chatTimeout  time.Duration
+	retention    time.Duration
+	shareSecret  string
 	log          *zap.Logger
 }
 
-func NewUpdater(telegram telegramService, db repository) *Updater {
+func NewUpdater(telegram telegramService, db repository, calendarUrl string, chatTimeout, trashRetention time.Duration, shareSecret string) *Updater {
 	u := &Updater{
 		telegram:     telegram,
 		chats:        new(sync.Map),
-		deleteChatCh: make(chan int64),
+		deleteChatCh: make(chan *chat.Chat),
 		db:           db,
+		calendarUrl:  calendarUrl,
+		chatTimeout:  chatTimeout,
+		retention:    trashRetention,
+		shareSecret:  shareSecret,
 		log:          l.Logger(),
 	}
 
@@ -32,12 +54,97 @@ func NewUpdater(telegram telegramService, db repository) *Updater {
 	return u
 }
 
+// newChat creates a chat session for the message, closing the one it replaces.
+// The replaced session is waited for, otherwise it could save its progress over the new one
+func (u *Updater) newChat(m domain.Message) *chat.Chat {
+	ct := chat.NewChat(m.ChatId, m.TelegramId, m.Lang, u.outCh, u.deleteChatCh, u.db, u.chatTimeout)
+
+	if prev, loaded := u.chats.Swap(m.ChatId, ct); loaded {
+		prev.(*chat.Chat).Close()
+		<-prev.(*chat.Chat).Done()
+		u.forgetConversation(m.ChatId)
+	} else {
+		activeChatsCount.Inc()
+	}
+
+	return ct
+}
+
 func (u *Updater) deleteInactiveChats() {
-	for chatId := range u.deleteChatCh {
-		u.deleteChat(chatId)
+	for ct := range u.deleteChatCh {
+		// session might have been replaced already, keep the new one
+		if u.chats.CompareAndDelete(ct.ChatId(), ct) {
+			activeChatsCount.Dec()
+		}
 	}
 }
 
+// deleteChat closes the chat session, if there is one, and forgets its saved progress
 func (u *Updater) deleteChat(chatId int64) {
-	u.chats.Delete(chatId)
+	u.closeChat(chatId)
+	u.forgetConversation(chatId)
+}
+
+func (u *Updater) closeChat(chatId int64) {
+	if ct, loaded := u.chats.LoadAndDelete(chatId); loaded {
+		ct.(*chat.Chat).Close()
+		activeChatsCount.Dec()
+	}
+}
+
+// closeChats ends all sessions on shutdown, their progress stays saved to be resumed.
+// Sessions are waited for, they may still be replying or saving progress
+func (u *Updater) closeChats() {
+	var closed []*chat.Chat
+	u.chats.Range(func(chatId, ct any) bool {
+		u.closeChat(chatId.(int64))
+		closed = append(closed, ct.(*chat.Chat))
+		return true
+	})
+
+	for _, ct := range closed {
+		<-ct.Done()
+	}
+}
+
+// resumeChats restarts conversations saved before shutdown, expired ones are dropped
+func (u *Updater) resumeChats(ctx context.Context) {
+	convs, err := u.db.GetConversations(ctx)
+	if err != nil {
+		u.log.Error("failed to get saved conversations", zap.Error(err))
+		return
+	}
+
+	for _, conv := range convs {
+		if u.chatTimeout > 0 && time.Since(conv.UpdatedAt) > u.chatTimeout {
+			u.outCh <- domain.Message{ChatId: conv.ChatId, Lang: u.language(conv.TelegramId, ""), Text: domain.ReplySessionTimedOut}
+			u.forgetConversation(conv.ChatId)
+			continue
+		}
+
+		ct := u.newChat(domain.Message{ChatId: conv.ChatId, TelegramId: conv.TelegramId, Lang: u.language(conv.TelegramId, "")})
+		if !chat.Resume(ct, conv, u.calendarUrl) {
+			u.log.Error("unknown conversation flow", zap.Int64("chat_id", conv.ChatId), zap.String("flow", conv.Flow))
+			u.deleteChat(conv.ChatId)
+		}
+	}
+
+	u.log.Info("resumed conversations", zap.Int("count", len(convs)))
+}
+
+// language replies to the user are translated into, the one picked in the profile or else telegram's one by its code
+func (u *Updater) language(tgId int64, code string) string {
+	user, err := u.db.GetUserByTelegramId(context.Background(), tgId)
+	if err != nil {
+		u.log.Error("failed to get user", zap.Int64("telegram_id", tgId), zap.Error(err))
+	} else if user.Language != "" {
+		return user.Language
+	}
+	return domain.Language(code)
+}
+
+func (u *Updater) forgetConversation(chatId int64) {
+	if _, err := u.db.DeleteConversation(context.Background(), chatId); err != nil {
+		u.log.Error("failed to delete conversation", zap.Int64("chat_id", chatId), zap.Error(err))
+	}
 }
@@ -0,0 +1,56 @@
+package updater
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vedomirr/remindista/internal/domain"
+	r "github.com/vedomirr/remindista/internal/entity/reminder"
+	"github.com/vedomirr/remindista/internal/service/chat"
+)
+
+// fakeChatRepo keeps the saved conversation of a tags session
+type fakeChatRepo struct {
+	fakeRepo
+
+	mu    sync.Mutex
+	saved int
+}
+
+func (f *fakeChatRepo) GetTagCounts(context.Context, int) ([]r.TagCount, error) {
+	return []r.TagCount{{Tag: "#go", Count: 1}}, nil
+}
+
+func (f *fakeChatRepo) SaveConversation(context.Context, domain.Conversation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.saved++
+	return nil
+}
+
+func (f *fakeChatRepo) DeleteConversation(context.Context, int64) (int, error) {
+	return 1, nil
+}
+
+func TestUpdater_closeChats(t *testing.T) {
+	upd := newTestUpdater(&fakeChatRepo{})
+	upd.chats, upd.deleteChatCh = new(sync.Map), make(chan *chat.Chat)
+	go upd.deleteInactiveChats()
+
+	ct := upd.newChat(domain.Message{ChatId: 1, TelegramId: 10})
+	chat.NewChatTags(ct)
+
+	upd.closeChats()
+
+	select {
+	case <-ct.Done():
+	default:
+		t.Fatal("closeChats() returned before the session was over")
+	}
+
+	// nothing sends after the sessions are closed, so the channel can be closed
+	close(upd.outCh)
+	for range upd.outCh {
+	}
+}
@@ -16,13 +16,16 @@ import (
 type telegramService interface {
 	ReceiveMessages(ctx context.Context) chan domain.Message
 	SendMessage(chatID int64, html string, keyboard domain.Keyboard) error
-	SendMessageMarkdownV2(chatID int64, html string, keyboard domain.Keyboard) error
+	SendMessageMarkdownV2(chatID int64, html string, keyboard domain.Keyboard) (messageId int, err error)
 }
 
 type repository interface {
 	GetAllUsers(ctx context.Context, limit int, offset int) (users []u.User, err error)
 	GetRemindersByUserIdAndTime(ctx context.Context, userId int, userTime time.Time) (rmds []r.Reminder, err error)
 	UpdateReminder(ctx context.Context, rmd r.Reminder) (affectd int, err error)
+	PurgeDeletedReminders(ctx context.Context, before time.Time) (affected int, err error)
+	SaveDelivery(ctx context.Context, chatId int64, messageId int, rmdId int) error
+	PurgeDeliveries(ctx context.Context, before time.Time) (affected int, err error)
 }
 
 type Worker struct {
@@ -30,19 +33,33 @@ type Worker struct {
 	db       repository
 	log      *zap.Logger
 	interval time.Duration
+
+	// deleted reminders stay in trash for retention, then they are purged
+	retention time.Duration
 }
 
-func NewWorker(telegram telegramService, repo repository, workInterval time.Duration) *Worker {
+const (
+	purgeInterval = time.Hour
+
+	// replies to reminders delivered earlier don't edit them
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+func NewWorker(telegram telegramService, repo repository, workInterval, trashRetention time.Duration) *Worker {
 	return &Worker{
-		telegram: telegram,
-		db:       repo,
-		interval: workInterval,
-		log:      l.Logger(),
+		telegram:  telegram,
+		db:        repo,
+		interval:  workInterval,
+		retention: trashRetention,
+		log:       l.Logger(),
 	}
 }
 
 func (w *Worker) Run(ctx context.Context) {
 	t := time.NewTicker(w.interval)
+	purge := time.NewTicker(purgeInterval)
+
+	w.purge()
 
 	for {
 		select {
@@ -51,6 +68,9 @@ func (w *Worker) Run(ctx context.Context) {
 				w.log.Error("users pagination error", zap.Error(err))
 			}
 
+		case <-purge.C:
+			w.purge()
+
 		case <-ctx.Done():
 			w.log.Info("shutting down worker service")
 			return
@@ -60,6 +80,23 @@ func (w *Worker) Run(ctx context.Context) {
 
 func (w *Worker) Stop() {}
 
+// purge drops reminders kept in trash for too long and old deliveries
+func (w *Worker) purge() {
+	purged, err := w.db.PurgeDeletedReminders(context.Background(), time.Now().Add(-w.retention))
+	if err != nil {
+		w.log.Error("failed to purge deleted reminders", zap.Error(err))
+		return
+	}
+
+	if purged > 0 {
+		w.log.Info("purged deleted reminders", zap.Int("count", purged))
+	}
+
+	if _, err := w.db.PurgeDeliveries(context.Background(), time.Now().Add(-deliveryRetention)); err != nil {
+		w.log.Error("failed to purge deliveries", zap.Error(err))
+	}
+}
+
 func (w *Worker) processUsers() (err error) {
 	routinesLimit := make(chan struct{}, 100) // limit the number of goroutines
 
@@ -94,12 +131,24 @@ func (w *Worker) processUser(user u.User, limit chan struct{}) {
 func (w *Worker) processReminder(rmd r.Reminder, user u.User, limit chan struct{}) {
 	defer func() { <-limit }()
 
-	if err := w.telegram.SendMessageMarkdownV2(user.ChatId, rmd.StringMdV2(), rmd.Keyboard()); err != nil {
-		w.log.Error("failed to send message", zap.Int64("chat id", user.ChatId), zap.Error(err))
+	// group reminders go to the group without buttons, they are managed by group commands
+	chatId, kb := user.ChatId, rmd.Keyboard()
+	if rmd.GroupChatId != 0 {
+		chatId, kb = rmd.GroupChatId, nil
+	}
+
+	// there is no telegram language without a message, reminders follow the one picked in the profile
+	lang := domain.Language(user.Language)
+
+	messageId, err := w.telegram.SendMessageMarkdownV2(chatId, rmd.StringMdV2(lang), domain.TrKeyboard(lang, kb))
+	if err != nil {
+		w.log.Error("failed to send message", zap.Int64("chat id", chatId), zap.Error(err))
+	} else if err := w.db.SaveDelivery(context.Background(), chatId, messageId, rmd.Id); err != nil {
+		w.log.Error("failed to save delivery", zap.Int("reminder id", rmd.Id), zap.Error(err))
 	}
 
 	rmd.UpdateNextReminder(user.Time(), user.FloorDuration(), user.CeilDuration())
-	w.log.Info("reminder updated", zap.Int("reminder_id", rmd.Id), zap.String("next_reminder", rmd.NextReminderString()))
+	w.log.Info("reminder updated", zap.Int("reminder_id", rmd.Id), zap.String("next_reminder", rmd.NextReminderString(domain.Languages[0])))
 
 	if _, err := w.db.UpdateReminder(context.Background(), rmd); err != nil {
 		w.log.Error("failed to update reminder", zap.Int("reminder id", rmd.Id), zap.Error(err))
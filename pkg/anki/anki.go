@@ -0,0 +1,392 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1" //nolint: gosec
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const (
+	fieldSeparator = "\x1f"
+
+	collectionLegacy = "collection.anki2"
+	collection21     = "collection.anki21"
+	collection21b    = "collection.anki21b"
+	mediaFile        = "media"
+
+	maxCollectionBytes = 64 << 20
+)
+
+var (
+	ErrorNoCollection = errors.New("package has no anki collection")
+	ErrorNotSupported = errors.New("package was exported in the latest anki format, export it with \"support older anki versions\" checked")
+	ErrorTooLarge     = errors.New("package is too large")
+
+	reLineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</div>|</p>|</li>`)
+	reTag       = regexp.MustCompile(`<[^>]*>`)
+	reSound     = regexp.MustCompile(`\[sound:[^\]]*\]`)
+	reNewLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// Note is a single anki note reduced to its first two fields and the deck of its first card
+type Note struct {
+	Front string
+	Back  string
+	Deck  string
+}
+
+// Import reads notes from an .apkg package
+func Import(data []byte) (notes []Note, err error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open package: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	// prefer the newer collection, legacy one only holds a stub note when another is present,
+	// so the latest format is refused before falling back to it
+	f, ok := files[collection21]
+	if !ok {
+		if _, ok = files[collection21b]; ok {
+			return nil, ErrorNotSupported
+		}
+		if f, ok = files[collectionLegacy]; !ok {
+			return nil, ErrorNoCollection
+		}
+	}
+
+	path, err := unpack(f)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(filepath.Dir(path)) }()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open collection: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	decks, err := readDecks(db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT n.flds, COALESCE(MIN(c.did), 0)
+FROM notes n
+LEFT JOIN cards c ON c.nid = n.id
+GROUP BY n.id
+ORDER BY n.id;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			flds string
+			did  int64
+		)
+
+		if err := rows.Scan(&flds, &did); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+
+		fields := strings.Split(flds, fieldSeparator)
+
+		note := Note{Front: htmlToText(fields[0]), Deck: decks[did]}
+		if len(fields) > 1 {
+			note.Back = htmlToText(fields[1])
+		}
+
+		if note.Front == "" {
+			continue
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// Export builds an .apkg package with a "Basic" note type, putting each note into a deck named after Note.Deck
+func Export(notes []Note) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "anki-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, collectionLegacy)
+	if err := writeCollection(path, notes); err != nil {
+		return nil, err
+	}
+
+	collection, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for name, content := range map[string][]byte{collectionLegacy: collection, mediaFile: []byte("{}")} {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s to package: %w", name, err)
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, fmt.Errorf("failed to write %s to package: %w", name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close package: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func unpack(f *zip.File) (path string, err error) {
+	if f.UncompressedSize64 > uint64(maxCollectionBytes) {
+		return "", ErrorTooLarge
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open collection: %w", err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	dir, err := os.MkdirTemp("", "anki-import-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	path = filepath.Join(dir, collectionLegacy)
+	out, err := os.Create(path)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to create temp collection: %w", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	if _, err := io.Copy(out, io.LimitReader(rc, maxCollectionBytes)); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to unpack collection: %w", err)
+	}
+
+	return path, nil
+}
+
+func readDecks(db *sql.DB) (map[int64]string, error) {
+	decks := make(map[int64]string)
+
+	var raw string
+	if err := db.QueryRow(`SELECT decks FROM col LIMIT 1;`).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("failed to query decks: %w", err)
+	}
+
+	colDecks := make(map[string]deck)
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &colDecks); err != nil {
+			return nil, fmt.Errorf("failed to parse decks: %w", err)
+		}
+	}
+
+	for _, d := range colDecks {
+		decks[d.Id] = d.Name
+	}
+
+	if len(decks) > 0 {
+		return decks, nil
+	}
+
+	// collections upgraded by anki 2.1.28+ keep decks in a separate table
+	rows, err := db.Query(`SELECT id, name FROM decks;`)
+	if err != nil {
+		return decks, nil //nolint: nilerr
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan deck: %w", err)
+		}
+		decks[id] = strings.ReplaceAll(name, fieldSeparator, "::")
+	}
+
+	return decks, rows.Err()
+}
+
+func writeCollection(path string, notes []Note) (err error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+	defer func() {
+		if errClose := db.Close(); errClose != nil && err == nil {
+			err = fmt.Errorf("failed to close collection: %w", errClose)
+		}
+	}()
+
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create collection schema: %w", err)
+	}
+
+	now := time.Now()
+	mod := now.Unix()
+	ids := newIdSource(now)
+
+	mid := ids.next()
+	decks := map[string]deck{strconv.Itoa(defaultDeckId): newDeck(defaultDeckId, defaultDeckName, mod)}
+	deckIds := map[string]int64{defaultDeckName: defaultDeckId}
+
+	for _, note := range notes {
+		name := deckName(note.Deck)
+		if _, ok := deckIds[name]; ok {
+			continue
+		}
+		did := ids.next()
+		deckIds[name] = did
+		decks[strconv.FormatInt(did, 10)] = newDeck(did, name, mod)
+	}
+
+	models := map[string]model{strconv.FormatInt(mid, 10): basicModel(mid, defaultDeckId, mod)}
+
+	conf, err := marshal(defaultConf(mid), "conf")
+	if err != nil {
+		return err
+	}
+	modelsJson, err := marshal(models, "models")
+	if err != nil {
+		return err
+	}
+	decksJson, err := marshal(decks, "decks")
+	if err != nil {
+		return err
+	}
+	dconf, err := marshal(defaultDeckConf(mod), "deck conf")
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(`INSERT INTO col VALUES (1, ?, ?, ?, 11, 0, 0, 0, ?, ?, ?, ?, '{}');`,
+		mod, now.UnixMilli(), now.UnixMilli(), conf, modelsJson, decksJson, dconf,
+	); err != nil {
+		return fmt.Errorf("failed to insert collection: %w", err)
+	}
+
+	for i, note := range notes {
+		nid, cid := ids.next(), ids.next()
+		front, back := textToHtml(note.Front), textToHtml(note.Back)
+
+		if _, err = tx.Exec(`INSERT INTO notes VALUES (?, ?, ?, ?, -1, '', ?, ?, ?, 0, '');`,
+			nid, guid(), mid, mod, front+fieldSeparator+back, note.Front, checksum(note.Front),
+		); err != nil {
+			return fmt.Errorf("failed to insert note: %w", err)
+		}
+
+		if _, err = tx.Exec(`INSERT INTO cards VALUES (?, ?, ?, 0, ?, -1, 0, 0, ?, 0, 0, 0, 0, 0, 0, 0, 0, '');`,
+			cid, nid, deckIds[deckName(note.Deck)], mod, i+1,
+		); err != nil {
+			return fmt.Errorf("failed to insert card: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func marshal(v any, name string) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	return string(b), nil
+}
+
+func deckName(name string) string {
+	if name = strings.TrimSpace(name); name == "" {
+		return defaultDeckName
+	}
+	return name
+}
+
+func htmlToText(s string) string {
+	s = reSound.ReplaceAllString(s, "")
+	s = reLineBreak.ReplaceAllString(s, "\n")
+	s = reTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\u00a0", " ")
+	s = reNewLines.ReplaceAllString(s, "\n\n")
+
+	return strings.TrimSpace(s)
+}
+
+func textToHtml(s string) string {
+	return strings.ReplaceAll(html.EscapeString(s), "\n", "<br>")
+}
+
+// checksum is the first 8 hex digits of sha1 of the sort field, as anki computes it
+func checksum(s string) int64 {
+	sum := sha1.Sum([]byte(s)) //nolint: gosec
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+func guid() string {
+	return strconv.FormatUint(rand.Uint64(), 36) //nolint: gosec
+}
+
+// idSource hands out unique millisecond based ids, the way anki generates them
+type idSource struct{ last int64 }
+
+func newIdSource(t time.Time) *idSource {
+	return &idSource{last: t.UnixMilli()}
+}
+
+func (s *idSource) next() int64 {
+	s.last++
+	return s.last
+}
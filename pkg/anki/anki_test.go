@@ -0,0 +1,111 @@
+package anki
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestExportImport(t *testing.T) {
+	notes := []Note{
+		{Front: "What does JOIN do?", Back: "Combines rows <from> two tables\nby a condition", Deck: "sql"},
+		{Front: "Goroutine", Back: "", Deck: ""},
+		{Front: "Channel & select", Back: "multiplexing", Deck: "go::concurrency"},
+	}
+
+	data, err := Export(notes)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	got, err := Import(data)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	want := []Note{
+		{Front: "What does JOIN do?", Back: "Combines rows <from> two tables\nby a condition", Deck: "sql"},
+		{Front: "Goroutine", Back: "", Deck: defaultDeckName},
+		{Front: "Channel & select", Back: "multiplexing", Deck: "go::concurrency"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Import() = %+v, want %+v", got, want)
+	}
+}
+
+func TestImport_LatestFormat(t *testing.T) {
+	// latest anki puts the real collection into anki21b and a legacy stub asking to update anki next to it
+	stub, err := Export([]Note{{Front: "Please update to the latest Anki version, then import the .colpkg/.apkg file again."}})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(stub), int64(len(stub)))
+	if err != nil {
+		t.Fatalf("failed to open stub package: %v", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		w, err := zw.Create(f.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.Copy(w, rc); err != nil {
+			t.Fatal(err)
+		}
+		_ = rc.Close()
+	}
+	w, err := zw.Create(collection21b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("zstd compressed collection")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if notes, err := Import(buf.Bytes()); !errors.Is(err, ErrorNotSupported) {
+		t.Errorf("Import() = %+v, %v, want ErrorNotSupported", notes, err)
+	}
+}
+
+func TestImport_NotAPackage(t *testing.T) {
+	if _, err := Import([]byte("definitely not a zip")); err == nil {
+		t.Error("Import() expected error for invalid package")
+	}
+}
+
+func TestHtmlToText(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "hello", "hello"},
+		{"line breaks", "one<br>two<br />three", "one\ntwo\nthree"},
+		{"divs", "<div>one</div><div>two</div>", "one\ntwo"},
+		{"entities", "a &amp; b&nbsp;&lt;c&gt;", "a & b <c>"},
+		{"sound", "word[sound:word.mp3]", "word"},
+		{"formatting", "<b>bold</b> and <i>italic</i>", "bold and italic"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := htmlToText(tc.input); got != tc.want {
+				t.Errorf("htmlToText(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package anki
+
+// schema of the legacy (v11) anki collection, the one every anki client is able to import
+const schema = `
+CREATE TABLE col (
+    id     integer primary key,
+    crt    integer not null,
+    mod    integer not null,
+    scm    integer not null,
+    ver    integer not null,
+    dty    integer not null,
+    usn    integer not null,
+    ls     integer not null,
+    conf   text not null,
+    models text not null,
+    decks  text not null,
+    dconf  text not null,
+    tags   text not null
+);
+CREATE TABLE notes (
+    id    integer primary key,
+    guid  text not null,
+    mid   integer not null,
+    mod   integer not null,
+    usn   integer not null,
+    tags  text not null,
+    flds  text not null,
+    sfld  integer not null,
+    csum  integer not null,
+    flags integer not null,
+    data  text not null
+);
+CREATE TABLE cards (
+    id     integer primary key,
+    nid    integer not null,
+    did    integer not null,
+    ord    integer not null,
+    mod    integer not null,
+    usn    integer not null,
+    type   integer not null,
+    queue  integer not null,
+    due    integer not null,
+    ivl    integer not null,
+    factor integer not null,
+    reps   integer not null,
+    lapses integer not null,
+    left   integer not null,
+    odue   integer not null,
+    odid   integer not null,
+    flags  integer not null,
+    data   text not null
+);
+CREATE TABLE revlog (
+    id      integer primary key,
+    cid     integer not null,
+    usn     integer not null,
+    ease    integer not null,
+    ivl     integer not null,
+    lastIvl integer not null,
+    factor  integer not null,
+    time    integer not null,
+    type    integer not null
+);
+CREATE TABLE graves (
+    usn  integer not null,
+    oid  integer not null,
+    type integer not null
+);
+CREATE INDEX ix_notes_usn ON notes (usn);
+CREATE INDEX ix_cards_usn ON cards (usn);
+CREATE INDEX ix_revlog_usn ON revlog (usn);
+CREATE INDEX ix_cards_nid ON cards (nid);
+CREATE INDEX ix_cards_sched ON cards (did, queue, due);
+CREATE INDEX ix_revlog_cid ON revlog (cid);
+CREATE INDEX ix_notes_csum ON notes (csum);
+`
+
+const (
+	defaultDeckId   = 1
+	defaultDeckName = "Default"
+
+	basicModelCss = ".card {\n font-family: arial;\n font-size: 20px;\n text-align: center;\n color: black;\n background-color: white;\n}\n"
+	latexPre      = "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage[utf8]{inputenc}\n\\usepackage{amssymb,amsmath}\n\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n"
+	latexPost     = "\\end{document}"
+)
+
+type (
+	deck struct {
+		Id               int64  `json:"id"`
+		Name             string `json:"name"`
+		Mod              int64  `json:"mod"`
+		Usn              int    `json:"usn"`
+		Desc             string `json:"desc"`
+		Dyn              int    `json:"dyn"`
+		Conf             int    `json:"conf"`
+		Collapsed        bool   `json:"collapsed"`
+		BrowserCollapsed bool   `json:"browserCollapsed"`
+		ExtendNew        int    `json:"extendNew"`
+		ExtendRev        int    `json:"extendRev"`
+		NewToday         [2]int `json:"newToday"`
+		RevToday         [2]int `json:"revToday"`
+		LrnToday         [2]int `json:"lrnToday"`
+		TimeToday        [2]int `json:"timeToday"`
+	}
+
+	model struct {
+		Id        int64      `json:"id"`
+		Name      string     `json:"name"`
+		Type      int        `json:"type"`
+		Mod       int64      `json:"mod"`
+		Usn       int        `json:"usn"`
+		Sortf     int        `json:"sortf"`
+		Did       int64      `json:"did"`
+		Tmpls     []template `json:"tmpls"`
+		Flds      []field    `json:"flds"`
+		Css       string     `json:"css"`
+		LatexPre  string     `json:"latexPre"`
+		LatexPost string     `json:"latexPost"`
+		Tags      []string   `json:"tags"`
+		Vers      []int      `json:"vers"`
+		Req       [][]any    `json:"req"`
+	}
+
+	template struct {
+		Name  string `json:"name"`
+		Ord   int    `json:"ord"`
+		Qfmt  string `json:"qfmt"`
+		Afmt  string `json:"afmt"`
+		Did   *int64 `json:"did"`
+		Bqfmt string `json:"bqfmt"`
+		Bafmt string `json:"bafmt"`
+	}
+
+	field struct {
+		Name   string   `json:"name"`
+		Ord    int      `json:"ord"`
+		Sticky bool     `json:"sticky"`
+		Rtl    bool     `json:"rtl"`
+		Font   string   `json:"font"`
+		Size   int      `json:"size"`
+		Media  []string `json:"media"`
+	}
+)
+
+func newDeck(id int64, name string, mod int64) deck {
+	return deck{Id: id, Name: name, Mod: mod, Usn: -1, Conf: 1, ExtendNew: 10, ExtendRev: 50}
+}
+
+func basicModel(id, did, mod int64) model {
+	return model{
+		Id:    id,
+		Name:  "Basic",
+		Mod:   mod,
+		Usn:   -1,
+		Did:   did,
+		Tmpls: []template{{Name: "Card 1", Qfmt: "{{Front}}", Afmt: "{{FrontSide}}\n\n<hr id=answer>\n\n{{Back}}"}},
+		Flds: []field{
+			{Name: "Front", Ord: 0, Font: "Arial", Size: 20, Media: []string{}},
+			{Name: "Back", Ord: 1, Font: "Arial", Size: 20, Media: []string{}},
+		},
+		Css:       basicModelCss,
+		LatexPre:  latexPre,
+		LatexPost: latexPost,
+		Tags:      []string{},
+		Vers:      []int{},
+		Req:       [][]any{{0, "all", []int{0}}},
+	}
+}
+
+func defaultConf(mid int64) map[string]any {
+	return map[string]any{
+		"nextPos":       1,
+		"estTimes":      true,
+		"activeDecks":   []int{defaultDeckId},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       defaultDeckId,
+		"newBury":       true,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      mid,
+		"collapseTime":  1200,
+	}
+}
+
+func defaultDeckConf(mod int64) map[string]any {
+	return map[string]any{
+		"1": map[string]any{
+			"id":       1,
+			"name":     "Default",
+			"mod":      mod,
+			"usn":      0,
+			"maxTaken": 60,
+			"autoplay": true,
+			"timer":    0,
+			"replayq":  true,
+			"dyn":      false,
+			"new": map[string]any{
+				"delays":        []float64{1, 10},
+				"ints":          []int{1, 4, 7},
+				"initialFactor": 2500,
+				"order":         1,
+				"perDay":        20,
+				"bury":          true,
+				"separate":      true,
+			},
+			"lapse": map[string]any{
+				"delays":      []float64{10},
+				"mult":        0,
+				"minInt":      1,
+				"leechFails":  8,
+				"leechAction": 0,
+			},
+			"rev": map[string]any{
+				"perDay":   100,
+				"ease4":    1.3,
+				"fuzz":     0.05,
+				"minSpace": 1,
+				"ivlFct":   1,
+				"maxIvl":   36500,
+				"bury":     true,
+			},
+		},
+	}
+}
@@ -0,0 +1,134 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	lineBreak    = "\r\n"
+	maxLineBytes = 75
+
+	dateTimeUtc = "20060102T150405Z"
+)
+
+// Calendar is a minimal VCALENDAR, enough for subscription feeds
+type Calendar struct {
+	ProductId string
+	Name      string
+	Location  *time.Location // only hints clients the calendar's zone, times are written in UTC
+	Events    []Event
+}
+
+type Event struct {
+	Uid         string
+	Summary     string
+	Description string
+	Categories  []string
+	Start       time.Time
+	Duration    time.Duration
+	Modified    time.Time
+}
+
+// Write renders the calendar according to RFC 5545
+func (c *Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + escape(c.ProductId))
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	if c.Location != nil {
+		lw.line("X-WR-TIMEZONE:" + c.Location.String())
+	}
+
+	for _, e := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escape(e.Uid))
+		lw.line("DTSTAMP:" + e.Modified.UTC().Format(dateTimeUtc))
+		lw.line(c.dateTime("DTSTART", e.Start))
+		if e.Duration > 0 {
+			lw.line("DURATION:" + duration(e.Duration))
+		}
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		if len(e.Categories) > 0 {
+			categories := make([]string, 0, len(e.Categories))
+			for _, category := range e.Categories {
+				categories = append(categories, escape(category))
+			}
+			lw.line("CATEGORIES:" + strings.Join(categories, ","))
+		}
+		lw.line("TRANSP:TRANSPARENT")
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+
+	return lw.err
+}
+
+// dateTime is written in UTC, local time with TZID would need a VTIMEZONE component describing the zone
+func (c *Calendar) dateTime(name string, t time.Time) string {
+	return name + ":" + t.UTC().Format(dateTimeUtc)
+}
+
+func duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	var str strings.Builder
+	str.WriteString("PT")
+
+	if h := int(d.Hours()); h > 0 {
+		str.WriteString(fmt.Sprintf("%dH", h))
+		d -= time.Duration(h) * time.Hour
+	}
+	str.WriteString(fmt.Sprintf("%dM", int(d.Minutes())))
+
+	return str.String()
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// lineWriter folds content lines longer than 75 octets without splitting utf-8 sequences
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	var str strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > maxLineBytes {
+			str.WriteString(lineBreak + " ")
+			n = 1
+		}
+		str.WriteRune(r)
+		n += size
+	}
+	str.WriteString(lineBreak)
+
+	_, lw.err = io.WriteString(lw.w, str.String())
+}
@@ -0,0 +1,70 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar_Write(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := Calendar{
+		ProductId: "-//remindista//EN",
+		Name:      "Reminders",
+		Location:  loc,
+		Events: []Event{{
+			Uid:         "1-0@remindista",
+			Summary:     "Joins; inner, outer",
+			Description: "line one\nline two",
+			Categories:  []string{"#sql"},
+			Start:       time.Date(2025, 3, 1, 7, 30, 0, 0, time.UTC),
+			Duration:    90 * time.Minute,
+			Modified:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	var buf strings.Builder
+	if err := c.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-TIMEZONE:Europe/Moscow\r\n",
+		"DTSTART:20250301T073000Z\r\n",
+		"DURATION:PT1H30M\r\n",
+		"SUMMARY:Joins\\; inner\\, outer\r\n",
+		"DESCRIPTION:line one\\nline two\r\n",
+		"CATEGORIES:#sql\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Write() output is missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	// TZID must refer to a VTIMEZONE component, which isn't written
+	if strings.Contains(buf.String(), "TZID=") {
+		t.Errorf("Write() output refers to a time zone it doesn't define:\n%s", buf.String())
+	}
+}
+
+func TestLineWriter_Folding(t *testing.T) {
+	var buf strings.Builder
+	lw := &lineWriter{w: &buf}
+	lw.line("SUMMARY:" + strings.Repeat("я", 50))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > maxLineBytes {
+			t.Errorf("line is %d octets long: %q", len(line), line)
+		}
+	}
+
+	if got := strings.ReplaceAll(buf.String(), "\r\n ", ""); got != "SUMMARY:"+strings.Repeat("я", 50)+"\r\n" {
+		t.Errorf("unfolded line = %q", got)
+	}
+}
@@ -0,0 +1,313 @@
+package mdcards
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxFileBytes  = 1 << 20
+	maxVaultBytes = 64 << 20
+)
+
+var (
+	ErrorTooLarge = errors.New("vault is too large")
+
+	reHeading  = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+	reQuestion = regexp.MustCompile(`^(?i)q:\s*(.*)$`)
+	reAnswer   = regexp.MustCompile(`^(?i)a:\s*(.*)$`)
+	reWikiLink = regexp.MustCompile(`\[\[([^\]|]+)(?:\|([^\]]+))?\]\]`)
+)
+
+// Card is a single question extracted from a markdown note
+type Card struct {
+	// Key identifies the card across imports: note path plus heading or question
+	Key   string
+	Front string
+	Back  string
+	Tag   string
+}
+
+// ParseVault extracts cards from every markdown note in a zipped vault
+func ParseVault(data []byte) (cards []Card, err error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vault: %w", err)
+	}
+
+	files := make([]*zip.File, 0, len(zr.File))
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !IsMarkdown(f.Name) || isHidden(f.Name) {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	root := commonRoot(files)
+
+	var total uint64
+	for _, f := range files {
+		if total += f.UncompressedSize64; total > maxVaultBytes {
+			return nil, ErrorTooLarge
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		cards = append(cards, Parse(strings.TrimPrefix(f.Name, root), content)...)
+	}
+
+	return cards, nil
+}
+
+// Parse extracts cards from a single markdown note. Every Q:/A: block becomes a card,
+// every heading with a body and no Q:/A: blocks inside becomes a card too.
+func Parse(notePath string, data []byte) []Card {
+	notePath = path.Clean(strings.ReplaceAll(notePath, "\\", "/"))
+
+	body, tag := frontMatter(string(data))
+	if tag == "" {
+		tag = folderTag(notePath)
+	}
+
+	p := &parser{path: notePath, tag: tag, keys: make(map[string]int)}
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileBytes)
+
+	for scanner.Scan() {
+		p.line(scanner.Text())
+	}
+	p.flush()
+
+	return p.cards
+}
+
+type parser struct {
+	path string
+	tag  string
+
+	inCode bool
+
+	heading   string
+	body      []string
+	hasBlocks bool
+
+	question []string
+	answer   []string
+	inAnswer bool
+
+	cards []Card
+	keys  map[string]int
+}
+
+func (p *parser) line(line string) {
+	trimmed := strings.TrimSpace(line)
+
+	if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+		p.inCode = !p.inCode
+	}
+
+	if !p.inCode {
+		if m := reHeading.FindStringSubmatch(trimmed); m != nil {
+			p.flush()
+			p.heading = cleanInline(m[2])
+			return
+		}
+
+		if m := reQuestion.FindStringSubmatch(trimmed); m != nil {
+			p.flushBlock()
+			p.hasBlocks = true
+			p.question = []string{m[1]}
+			return
+		}
+
+		if m := reAnswer.FindStringSubmatch(trimmed); m != nil && p.question != nil {
+			p.inAnswer = true
+			p.answer = []string{m[1]}
+			return
+		}
+	}
+
+	switch {
+	case p.question != nil && p.inAnswer:
+		p.answer = append(p.answer, line)
+	case p.question != nil:
+		p.question = append(p.question, line)
+	default:
+		p.body = append(p.body, line)
+	}
+}
+
+// flush closes the current heading section
+func (p *parser) flush() {
+	p.flushBlock()
+
+	if p.heading != "" && !p.hasBlocks {
+		if body := joinLines(p.body); body != "" {
+			p.add("#"+p.heading, p.heading, body)
+		}
+	}
+
+	p.heading, p.body, p.hasBlocks = "", nil, false
+}
+
+// flushBlock closes the current Q:/A: block
+func (p *parser) flushBlock() {
+	if p.question != nil {
+		if question := joinLines(p.question); question != "" {
+			p.add("?"+question, question, joinLines(p.answer))
+		}
+	}
+
+	p.question, p.answer, p.inAnswer = nil, nil, false
+}
+
+func (p *parser) add(anchor, front, back string) {
+	key := p.path + anchor
+
+	// same heading may repeat within a note, keep keys unique
+	if n := p.keys[key]; n > 0 {
+		p.keys[key]++
+		key = fmt.Sprintf("%s#%d", key, n+1)
+	} else {
+		p.keys[key] = 1
+	}
+
+	p.cards = append(p.cards, Card{Key: key, Front: front, Back: back, Tag: p.tag})
+}
+
+// frontMatter cuts yaml front matter off the note and returns its first tag
+func frontMatter(s string) (body, tag string) {
+	s = strings.TrimPrefix(s, "\ufeff")
+	if !strings.HasPrefix(s, "---\n") && !strings.HasPrefix(s, "---\r\n") {
+		return s, ""
+	}
+
+	lines := strings.Split(s, "\n")
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
+			break
+		}
+	}
+
+	if end < 0 {
+		return s, ""
+	}
+
+	for i := 1; i < end; i++ {
+		line := strings.TrimSpace(lines[i])
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || (strings.TrimSpace(key) != "tags" && strings.TrimSpace(key) != "tag") {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), "[]")
+		if value != "" {
+			tag = strings.Split(value, ",")[0]
+			break
+		}
+
+		// list form: tags:\n  - first
+		if i+1 < end {
+			if item, ok := strings.CutPrefix(strings.TrimSpace(lines[i+1]), "-"); ok {
+				tag = item
+			}
+		}
+		break
+	}
+
+	tag = strings.TrimPrefix(strings.Trim(strings.TrimSpace(tag), `"'`), "#")
+
+	return strings.Join(lines[end+1:], "\n"), tag
+}
+
+func folderTag(notePath string) string {
+	dir := path.Dir(notePath)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(strings.ReplaceAll(strings.Trim(dir, "/"), "/", "_")), "_")
+}
+
+func cleanInline(s string) string {
+	return reWikiLink.ReplaceAllStringFunc(s, func(link string) string {
+		m := reWikiLink.FindStringSubmatch(link)
+		if m[2] != "" {
+			return m[2]
+		}
+		return m[1]
+	})
+}
+
+func joinLines(lines []string) string {
+	return strings.TrimSpace(cleanInline(strings.Join(lines, "\n")))
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxFileBytes {
+		return nil, fmt.Errorf("%s: %w", f.Name, ErrorTooLarge)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxFileBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+
+	return content, nil
+}
+
+// commonRoot finds the vault folder most archivers wrap the notes into
+func commonRoot(files []*zip.File) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	root, _, ok := strings.Cut(files[0].Name, "/")
+	if !ok {
+		return ""
+	}
+	root += "/"
+
+	for _, f := range files {
+		if !strings.HasPrefix(f.Name, root) {
+			return ""
+		}
+	}
+
+	return root
+}
+
+// IsMarkdown reports whether the file name looks like a markdown note
+func IsMarkdown(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".md" || ext == ".markdown"
+}
+
+func isHidden(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package mdcards
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name  string
+		path  string
+		input string
+		want  []Card
+	}{
+		{
+			name:  "headings",
+			path:  "sql/joins.md",
+			input: "# Joins\n\n## Inner join\nRows matching in both tables.\n\n## Empty heading\n\n## Left join\nAll rows from the left table.\n",
+			want: []Card{
+				{Key: "sql/joins.md#Inner join", Front: "Inner join", Back: "Rows matching in both tables.", Tag: "sql"},
+				{Key: "sql/joins.md#Left join", Front: "Left join", Back: "All rows from the left table.", Tag: "sql"},
+			},
+		},
+		{
+			name:  "question blocks replace heading",
+			path:  "notes.md",
+			input: "# Go\nQ: What is a goroutine?\nA: A lightweight thread\nmanaged by runtime.\n\nQ: [[Channel|Channels]]?\nA: Typed conduits.\n",
+			want: []Card{
+				{Key: "notes.md?What is a goroutine?", Front: "What is a goroutine?", Back: "A lightweight thread\nmanaged by runtime.", Tag: ""},
+				{Key: "notes.md?Channels?", Front: "Channels?", Back: "Typed conduits.", Tag: ""},
+			},
+		},
+		{
+			name:  "front matter tag wins over folder",
+			path:  "folder/sub/note.md",
+			input: "---\ntitle: x\ntags:\n  - \"#golang\"\n  - other\n---\n# Slices\nBacked by arrays.\n",
+			want: []Card{
+				{Key: "folder/sub/note.md#Slices", Front: "Slices", Back: "Backed by arrays.", Tag: "golang"},
+			},
+		},
+		{
+			name:  "code fences",
+			path:  "a/b c/note.md",
+			input: "# Shell\n```bash\n# not a heading\nQ: not a question\n```\n",
+			want: []Card{
+				{Key: "a/b c/note.md#Shell", Front: "Shell", Back: "```bash\n# not a heading\nQ: not a question\n```", Tag: "a_b_c"},
+			},
+		},
+		{
+			name:  "duplicate headings",
+			path:  "n.md",
+			input: "## Example\none\n## Example\ntwo\n",
+			want: []Card{
+				{Key: "n.md#Example", Front: "Example", Back: "one"},
+				{Key: "n.md#Example#2", Front: "Example", Back: "two"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Parse(tc.path, []byte(tc.input)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseVault(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"Vault/go/basics.md":           "# Maps\nHash tables.\n",
+		"Vault/.obsidian/workspace.md": "# Ignored\nbody\n",
+		"Vault/image.png":              "png",
+	} {
+		w, _ := zw.Create(name)
+		_, _ = w.Write([]byte(content))
+	}
+	_ = zw.Close()
+
+	got, err := ParseVault(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseVault() error = %v", err)
+	}
+
+	want := []Card{{Key: "go/basics.md#Maps", Front: "Maps", Back: "Hash tables.", Tag: "go"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseVault() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package render
+
+import "strings"
+
+// HTML renders telegram's HTML subset, see https://core.telegram.org/bots/api#html-style
+type HTML struct{}
+
+var htmlTags = map[Style]string{
+	Bold:      "b",
+	Italic:    "i",
+	Underline: "u",
+	Strike:    "s",
+	Spoiler:   "tg-spoiler",
+	Code:      "code",
+}
+
+// only these have to be replaced with entities, telegram supports no other named ones
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// EscapeHTML escapes text to be put into HTML as it is
+func EscapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+func (HTML) Render(c Content) string {
+	return renderMarkup(HTML{}, c)
+}
+
+func (HTML) ParseMode() string {
+	return "HTML"
+}
+
+func (HTML) open(style Style) string  { return "<" + htmlTags[style] + ">" }
+func (HTML) close(style Style) string { return "</" + htmlTags[style] + ">" }
+
+func (HTML) escape(s string, _ bool) string { return EscapeHTML(s) }
+
+func (HTML) separator(_, _ string) string { return "" }
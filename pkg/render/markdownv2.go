@@ -0,0 +1,117 @@
+package render
+
+import (
+	"strings"
+)
+
+// MarkdownV2 renders telegram's MarkdownV2, see https://core.telegram.org/bots/api#markdownv2-style
+type MarkdownV2 struct{}
+
+var mdV2Markers = map[Style]string{
+	Bold:      "*",
+	Italic:    "_",
+	Underline: "__",
+	Strike:    "~",
+	Spoiler:   "||",
+	Code:      "`",
+}
+
+/*
+Inside pre and code entities, all '`' and '\' characters must be escaped with a preceding '\' character.
+In all other places characters '_', '*', '[', ']', '(', ')', '~', '`', '>', '#', '+', '-', '=', '|', '{', '}', '.', '!' must be escaped with the preceding character '\'.
+Backslash escapes any character, so it's escaped itself everywhere.
+*/
+var (
+	mdV2Escaper = strings.NewReplacer(
+		"\\", "\\\\", "_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)", "~", "\\~", "`", "\\`",
+		">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-", "=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+	)
+	mdV2CodeEscaper = strings.NewReplacer("\\", "\\\\", "`", "\\`")
+)
+
+// EscapeMarkdownV2 escapes text to be put into MarkdownV2 as it is
+func EscapeMarkdownV2(s string) string {
+	return mdV2Escaper.Replace(s)
+}
+
+func (MarkdownV2) Render(c Content) string {
+	return renderMarkup(MarkdownV2{}, c)
+}
+
+func (MarkdownV2) ParseMode() string {
+	return "MarkdownV2"
+}
+
+func (MarkdownV2) open(style Style) string  { return mdV2Markers[style] }
+func (MarkdownV2) close(style Style) string { return mdV2Markers[style] }
+
+func (MarkdownV2) escape(s string, code bool) string {
+	if code {
+		return mdV2CodeEscaper.Replace(s)
+	}
+	return EscapeMarkdownV2(s)
+}
+
+// separator splits italic and underline markers with an empty bold entity, `___` is ambiguous otherwise
+func (MarkdownV2) separator(prev, next string) string {
+	if strings.HasSuffix(prev, "_") && strings.HasPrefix(next, "_") {
+		return "**"
+	}
+	return ""
+}
+
+// ParseMarkdownV2 reads MarkdownV2 back into content, so replies written in it can be rendered in other markups.
+// Links and block quotes aren't supported, their characters are kept as text
+func ParseMarkdownV2(s string) Content {
+	var (
+		c     Content
+		text  strings.Builder
+		style Style
+	)
+
+	flush := func() {
+		c = c.Add(style, text.String())
+		text.Reset()
+	}
+	toggle := func(st Style) {
+		flush()
+		style ^= st
+	}
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		if ch == '\\' && i+1 < len(s) {
+			i++
+			text.WriteByte(s[i])
+			continue
+		}
+
+		if style&Code != 0 && ch != '`' {
+			text.WriteByte(ch)
+			continue
+		}
+
+		switch {
+		case ch == '`':
+			toggle(Code)
+		case ch == '*':
+			toggle(Bold)
+		case strings.HasPrefix(s[i:], "__"):
+			toggle(Underline)
+			i++
+		case ch == '_':
+			toggle(Italic)
+		case ch == '~':
+			toggle(Strike)
+		case strings.HasPrefix(s[i:], "||"):
+			toggle(Spoiler)
+			i++
+		default:
+			text.WriteByte(ch)
+		}
+	}
+	flush()
+
+	return c
+}
@@ -0,0 +1,18 @@
+package render
+
+import "strings"
+
+// Plain renders text only, for transports without markup
+type Plain struct{}
+
+func (Plain) Render(c Content) string {
+	var str strings.Builder
+	for _, span := range c {
+		str.WriteString(span.Text)
+	}
+	return str.String()
+}
+
+func (Plain) ParseMode() string {
+	return ""
+}
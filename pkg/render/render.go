@@ -0,0 +1,140 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Style of a span, styles combine as flags
+type Style int
+
+const (
+	Bold Style = 1 << iota
+	Italic
+	Underline
+	Strike
+	Spoiler
+	Code // can't hold other styles in telegram, they're dropped from code spans
+)
+
+// styles in the order they're opened
+var styles = []Style{Bold, Italic, Underline, Strike, Spoiler, Code}
+
+// Span is a piece of text in a single style, zero style is plain text
+type Span struct {
+	Style Style
+	Text  string
+}
+
+// Content is the structured message renderers emit in their markup
+type Content []Span
+
+// Add appends text in the style, merging it into the last span of the same style
+func (c Content) Add(style Style, s string) Content {
+	if s == "" {
+		return c
+	}
+
+	if style&Code != 0 {
+		style = Code
+	}
+
+	if n := len(c); n > 0 && c[n-1].Style == style {
+		c[n-1].Text += s
+		return c
+	}
+
+	return append(c, Span{Style: style, Text: s})
+}
+
+// Append appends spans of the other content
+func (c Content) Append(other Content) Content {
+	for _, span := range other {
+		c = c.Add(span.Style, span.Text)
+	}
+	return c
+}
+
+func (c Content) Text(s string) Content    { return c.Add(0, s) }
+func (c Content) Bold(s string) Content    { return c.Add(Bold, s) }
+func (c Content) Italic(s string) Content  { return c.Add(Italic, s) }
+func (c Content) Strike(s string) Content  { return c.Add(Strike, s) }
+func (c Content) Spoiler(s string) Content { return c.Add(Spoiler, s) }
+func (c Content) Code(s string) Content    { return c.Add(Code, s) }
+
+// String is the text without any markup
+func (c Content) String() string {
+	return Plain{}.Render(c)
+}
+
+// Renderer emits content in the markup of a transport
+type Renderer interface {
+	Render(c Content) string
+	// ParseMode is telegram's name of the markup, empty for plain text
+	ParseMode() string
+}
+
+// ByName picks renderer by its name: `markdownv2`, `html` or `plain`
+func ByName(name string) (Renderer, error) {
+	switch strings.ToLower(name) {
+	case "markdownv2", "":
+		return MarkdownV2{}, nil
+	case "html":
+		return HTML{}, nil
+	case "plain":
+		return Plain{}, nil
+	default:
+		return nil, fmt.Errorf("unknown message format %q", name)
+	}
+}
+
+// markup describes how a renderer wraps and escapes styled text
+type markup interface {
+	open(style Style) string
+	close(style Style) string
+	escape(s string, code bool) string
+	// separator is put between two markers that would read as another one together
+	separator(prev, next string) string
+}
+
+// renderMarkup writes spans keeping styles open across spans that share them,
+// styles are closed in the reverse order they were opened so the markup stays nested
+func renderMarkup(m markup, c Content) string {
+	var str strings.Builder
+	var opened []Style
+
+	// prev is the marker written last if no text followed it
+	prev := ""
+	marker := func(mk string) {
+		str.WriteString(m.separator(prev, mk) + mk)
+		prev = mk
+	}
+
+	for _, span := range c {
+		keep := 0
+		for keep < len(opened) && span.Style&opened[keep] != 0 {
+			keep++
+		}
+		for i := len(opened) - 1; i >= keep; i-- {
+			marker(m.close(opened[i]))
+		}
+		opened = opened[:keep]
+
+		for _, style := range styles {
+			if span.Style&style != 0 && !slices.Contains(opened, style) {
+				marker(m.open(style))
+				opened = append(opened, style)
+			}
+		}
+
+		str.WriteString(m.escape(span.Text, span.Style&Code != 0))
+		prev = ""
+	}
+
+	for i := len(opened) - 1; i >= 0; i-- {
+		marker(m.close(opened[i]))
+	}
+
+	return str.String()
+}
@@ -0,0 +1,97 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// goldenCases are rendered by every renderer and compared to testdata/<renderer>.golden
+var goldenCases = []struct {
+	name    string
+	content Content
+}{
+	{"reserved characters", Content{}.Text("1+1=2! (a.k.a. [x]) {y} #tag > |z| ~w~ *v* _u_ `t` -s")},
+	{"backslash", Content{}.Text(`C:\Users\new`).Code(`\d+`)},
+	{"code with backticks", Content{}.Code("a `quoted` *word* _under_ <b>")},
+	{"html entities", Content{}.Text("<b>bold</b> &amp; & < >").Bold("a<b")},
+	{"nested styles", Content{}.Bold("bold ").Add(Bold|Italic, "both").Italic(" italic")},
+	{"italic next to underline", Content{}.Italic("it").Add(Underline, "under").Add(Italic|Underline, "both")},
+	{"spoiler with highlight", Content{}.Spoiler("prompt with ").Add(Spoiler|Bold, "match").Spoiler(".")},
+	{"card", Content{}.Text("Joins: inner, outer\n#sql\n").Spoiler("LEFT JOIN keeps (all) rows").Text("\n").Code("#3").Text(" ").Code("every 2 days").Text(" ⏸ ").Italic("paused")},
+	{"deleted", Content{}.Text("#3 ").Strike("Joins - inner.").Text("\n").Italic("удалено")},
+	{"empty", nil},
+}
+
+func TestRenderers_Golden(t *testing.T) {
+	for _, name := range []string{"markdownv2", "html", "plain"} {
+		t.Run(name, func(t *testing.T) {
+			renderer, err := ByName(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got strings.Builder
+			for _, tc := range goldenCases {
+				fmt.Fprintf(&got, "-- %s --\n%s\n", tc.name, renderer.Render(tc.content))
+			}
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got.String()), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != string(want) {
+				t.Errorf("output differs from %s, run with -update to review the diff:\n%s", golden, got.String())
+			}
+		})
+	}
+}
+
+func TestParseMarkdownV2(t *testing.T) {
+	for _, tc := range goldenCases {
+		if got := ParseMarkdownV2(MarkdownV2{}.Render(tc.content)); !reflect.DeepEqual(got, tc.content) {
+			t.Errorf("%s: parsed %#v, want %#v", tc.name, got, tc.content)
+		}
+	}
+
+	testCases := []struct {
+		in   string
+		want Content
+	}{
+		{"Say __yes__ or __no__\\.", Content{}.Text("Say ").Add(Underline, "yes").Text(" or ").Add(Underline, "no").Text(".")},
+		{"Set it with /update\\_user\\.", Content{}.Text("Set it with /update_user.")},
+		{"`every 1 day` `09:00-18:00`", Content{}.Code("every 1 day").Text(" ").Code("09:00-18:00")},
+		{"___both___", Content{}.Add(Italic|Underline, "both")},
+		{"*%s* \\(%d\\)", Content{}.Bold("%s").Text(" (%d)")},
+	}
+
+	for _, tc := range testCases {
+		if got := ParseMarkdownV2(tc.in); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseMarkdownV2(%q) = %#v, want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	if _, err := ByName("bbcode"); err == nil {
+		t.Error("ByName(bbcode) error = nil, want unknown format")
+	}
+
+	r, err := ByName("HTML")
+	if err != nil || r.ParseMode() != "HTML" {
+		t.Errorf("ByName(HTML) = %v, %v", r, err)
+	}
+}